@@ -20,6 +20,109 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// DeletionPolicyDelete removes the namespaces and secrets created for
+	// each context when the Cluster is deleted.
+	DeletionPolicyDelete = "Delete"
+
+	// DeletionPolicyRetain leaves the namespaces and secrets created for
+	// each context in place when the Cluster is deleted.
+	DeletionPolicyRetain = "Retain"
+)
+
+// Condition types set on the Cluster.
+const (
+	// ConditionReady is True when the kubeconfig was loaded and every
+	// context has been exported and is reachable.
+	ConditionReady = "Ready"
+
+	// ConditionKubeconfigLoaded is True when the source kubeconfig could
+	// be read and its contexts listed.
+	ConditionKubeconfigLoaded = "KubeconfigLoaded"
+
+	// ConditionDegraded is True when one or more contexts could not be
+	// exported or are unreachable.
+	ConditionDegraded = "Degraded"
+)
+
+// Reasons used on the Cluster conditions.
+const (
+	ReasonReconciled       = "Reconciled"
+	ReasonLoaded           = "Loaded"
+	ReasonLoadFailed       = "LoadFailed"
+	ReasonContextsReady    = "ContextsReady"
+	ReasonContextsNotReady = "ContextsNotReady"
+)
+
+// Reasons used on each entry in the cluster status.
+const (
+	// ReasonAvailable is set when the context was exported and the
+	// cluster is reachable.
+	ReasonAvailable = "Available"
+
+	// ReasonUnreachable is set when the context was exported but the
+	// cluster could not be reached using the exported kubeconfig.
+	ReasonUnreachable = "Unreachable"
+
+	// ReasonInvalidContext is set when the context, its cluster or its
+	// user could not be read from the kubeconfig.
+	ReasonInvalidContext = "InvalidContext"
+
+	// ReasonInvalidTarget is set when the secret name or secret format
+	// for the context cannot be used, e.g. a secret name Cluster API does
+	// not recognise, two kinds of data written under the same key or a
+	// secret that already exists and is not managed by this Cluster.
+	ReasonInvalidTarget = "InvalidTarget"
+
+	// ReasonUnsupportedProvider is set when the authentication method of
+	// the context is not supported.
+	ReasonUnsupportedProvider = "UnsupportedProvider"
+
+	// ReasonCredentialsFailed is set when credentials for the context
+	// could not be generated.
+	ReasonCredentialsFailed = "CredentialsFailed"
+
+	// ReasonNamespaceFailed is set when the namespace for the context
+	// could not be created.
+	ReasonNamespaceFailed = "NamespaceFailed"
+
+	// ReasonSecretFailed is set when the secret for the context could not
+	// be written.
+	ReasonSecretFailed = "SecretFailed"
+
+	// ReasonClusterAPIFailed is set when the placeholder Cluster API
+	// Cluster for the context could not be written.
+	ReasonClusterAPIFailed = "ClusterAPIFailed"
+
+	// ReasonUnauthorized is set when the cluster rejected the exported
+	// credentials.
+	ReasonUnauthorized = "Unauthorized"
+
+	// ReasonForbidden is set when the exported credentials are not allowed
+	// to list namespaces on the cluster.
+	ReasonForbidden = "Forbidden"
+
+	// ReasonCertificateSANMismatch is set when the cluster is unreachable
+	// because the server certificate does not include the remapped
+	// address or tls-server-name.
+	ReasonCertificateSANMismatch = "CertificateSANMismatch"
+
+	// ReasonCertificateUnknownAuthority is set when the cluster is
+	// unreachable because the server certificate is not signed by the CA
+	// in the kubeconfig.
+	ReasonCertificateUnknownAuthority = "CertificateUnknownAuthority"
+
+	// ReasonCertificateExpired is set when the cluster is unreachable
+	// because the server certificate has expired or is not yet valid.
+	ReasonCertificateExpired = "CertificateExpired"
+
+	// ReasonPassthroughExec is set when the context was exported with the
+	// exec plugin of the source kubeconfig. The cluster is reachable but
+	// the credentials could not be checked, as the operator cannot run
+	// the plugin.
+	ReasonPassthroughExec = "PassthroughExec"
+)
+
 // ClusterSpec defines the desired state of Cluster.
 type ClusterSpec struct {
 	// Additional Domains are domains that you want to accept for
@@ -34,6 +137,30 @@ type ClusterSpec struct {
 	// +optional
 	AdditionalDomains []string `json:"additionalDomains,omitempty"`
 
+	// ArgoCD, when set, also exports each context as an Argo CD cluster
+	// secret.
+	//
+	// +optional
+	ArgoCD *ArgoCDSink `json:"argoCD,omitempty"`
+
+	// Contexts selects which contexts from the kubeconfig are exported.
+	//
+	// By default every context whose server is in the list of allowed
+	// domains is exported.
+	//
+	// +optional
+	Contexts *ContextSelector `json:"contexts,omitempty"`
+
+	// DeletionPolicy controls what happens to the namespaces and secrets
+	// created by the operator when this Cluster is deleted.
+	//
+	// Delete will remove them, Retain will leave them in place.
+	//
+	// +optional
+	// +kubebuilder:default=Delete
+	// +kubebuilder:validation:Enum=Delete;Retain
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
 	// FirewallFormat is the format of the firewall rules that will be
 	// generated.
 	//
@@ -42,12 +169,47 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Enum=iptables;nftables;ufw;firewalld;ipfw;pf
 	FirewallFormat string `json:"firewallFormat,omitempty"`
 
+	// HostPathMappings rewrite absolute file paths referenced by the
+	// kubeconfig, such as `client-certificate` or `certificate-authority`,
+	// from the host to where they are mounted in the controller pod.
+	// Relative paths are resolved against the kubeconfig file.
+	//
+	// Only files under the directory of the kubeconfig or the pod path of
+	// a mapping can be read. Kubeconfigs loaded from KubeConfigSource may
+	// not reference files.
+	//
+	// +optional
+	HostPathMappings []HostPathMapping `json:"hostPathMappings,omitempty"`
+
+	// KubeConfigDir is a glob matching kubeconfig files on the
+	// controller, e.g. `/tmp/kubeconfigs/*.yaml`. A plain directory
+	// matches every file in it. Matches are merged after KubeConfigPath
+	// and KubeConfigPaths in lexical order.
+	//
+	// +optional
+	KubeConfigDir string `json:"kubeConfigDir,omitempty"`
+
 	// KubeConfigPath is the path on the controller where the kubeconfig
 	// file is mounted.
 	//
 	// +optional
 	KubeConfigPath string `json:"kubeConfigPath,omitempty"`
 
+	// KubeConfigPaths is a list of kubeconfig files merged in the same way
+	// as a colon separated KUBECONFIG: the first file to define a context,
+	// cluster or user wins. Differing definitions are reported in
+	// status.conflicts.
+	//
+	// +optional
+	KubeConfigPaths []string `json:"kubeConfigPaths,omitempty"`
+
+	// KubeConfigSource loads the kubeconfig from a Secret or ConfigMap
+	// instead of from KubeConfigPath. Changes to the referenced object
+	// trigger an immediate reconciliation.
+	//
+	// +optional
+	KubeConfigSource *KubeConfigSource `json:"kubeConfigSource,omitempty"`
+
 	// NamespacePrefix is a prefix that will be used to create the
 	// namespace for the cluster.
 	//
@@ -56,11 +218,34 @@ type ClusterSpec struct {
 	// +kubebuilder:default=cluster
 	NamespacePrefix string `json:"namespacePrefix,omitempty"`
 
+	// Overrides customise how individual contexts are exported.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=context
+	Overrides []ContextOverride `json:"overrides,omitempty"`
+
+	// Prune enables garbage collection of the namespaces and secrets
+	// created for contexts that no longer exist in the kubeconfig.
+	//
+	// +optional
+	// +kubebuilder:default=true
+	Prune *bool `json:"prune,omitempty"`
+
+	// PruneGracePeriod is how long a context must be missing from the
+	// kubeconfig before its namespace and secret are pruned. When unset,
+	// they are pruned on the next reconciliation.
+	//
+	// +optional
+	PruneGracePeriod metav1.Duration `json:"pruneGracePeriod,omitempty"`
+
 	// ReconcileInterval is the interval at which the controller will
-	// reconcile the cluster.
+	// reconcile the cluster. Changes to the kubeconfig trigger
+	// reconciliation immediately, so this only needs to be short enough
+	// to notice clusters becoming unreachable.
 	//
 	// +optional
-	// +kubebuilder:default="30s"
+	// +kubebuilder:default="5m"
 	ReconcileInterval metav1.Duration `json:"reconcileInterval,omitempty"`
 
 	// RemapToIp is the IP address that the localhost domain will be
@@ -70,30 +255,443 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Format=ipv4
 	RemapToIp string `json:"remapToIp,omitempty"`
 
+	// SecretFormat controls the keys written to each kubeconfig secret.
+	// It may be overridden per context in Overrides.
+	//
+	// +optional
+	SecretFormat *SecretFormat `json:"secretFormat,omitempty"`
+
+	// SetTLSServerName writes the original server host as
+	// `tls-server-name` when the server address is remapped to RemapToIp.
+	// This allows clusters whose certificates do not include the remap
+	// IP in their SANs to be reached without being recreated.
+	//
+	// +optional
+	SetTLSServerName bool `json:"setTLSServerName,omitempty"`
+
 	// Suspend will suspend the cluster.
 	//
 	// +optional
 	Suspend bool `json:"suspend,omitempty"`
 }
 
+// ArgoCDSink configures the Argo CD cluster secrets written for each
+// context.
+type ArgoCDSink struct {
+	// Namespace is the namespace Argo CD is installed in.
+	//
+	// +optional
+	// +kubebuilder:default=argocd
+	Namespace string `json:"namespace,omitempty"`
+
+	// Project restricts the clusters to a single Argo CD project.
+	//
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Labels are additional labels set on each cluster secret, for
+	// example to be picked up by an ApplicationSet cluster generator.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+const (
+	// KubeConfigSourceSecret reads the kubeconfig from a Secret.
+	KubeConfigSourceSecret = "Secret"
+
+	// KubeConfigSourceConfigMap reads the kubeconfig from a ConfigMap.
+	KubeConfigSourceConfigMap = "ConfigMap"
+)
+
+// HostPathMapping maps a path prefix on the host to a path prefix in the
+// controller pod.
+type HostPathMapping struct {
+	// Host is the path prefix as written in the kubeconfig, e.g. /home/me
+	//
+	// +required
+	Host string `json:"host"`
+
+	// Pod is the path prefix the host path is mounted at, e.g.
+	// /host/home/me
+	//
+	// +required
+	Pod string `json:"pod"`
+}
+
+// KubeConfigSource references a Secret or ConfigMap holding a kubeconfig.
+//
+// There is deliberately no namespace field. The object is read with the
+// permissions of the operator, so a reference to another namespace would
+// let anyone able to create a Cluster read any Secret in the cluster.
+type KubeConfigSource struct {
+	// Kind is the kind of the object holding the kubeconfig.
+	//
+	// +required
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// Name is the name of the Secret or ConfigMap. It is always read from
+	// the namespace of the Cluster.
+	//
+	// +required
+	Name string `json:"name"`
+
+	// Key is the key the kubeconfig is stored under.
+	//
+	// +optional
+	// +kubebuilder:default=value
+	Key string `json:"key,omitempty"`
+}
+
+// ContextSelector selects contexts from the kubeconfig.
+//
+// A context is exported when it matches at least one Include entry (or
+// Include is empty) and does not match any Exclude entry.
+type ContextSelector struct {
+	// Include lists the contexts to export.
+	//
+	// +optional
+	Include []ContextMatcher `json:"include,omitempty"`
+
+	// Exclude lists the contexts that must not be exported. Exclusions
+	// take precedence over inclusions.
+	//
+	// +optional
+	Exclude []ContextMatcher `json:"exclude,omitempty"`
+}
+
+// ContextMatcher matches a context by name, cluster name or user name.
+//
+// Each field is a glob supporting `*` and `?`, or a regular expression
+// when wrapped in slashes, for example `/^kind-(dev|test)$/`. All fields
+// that are set must match, and at least one must be set.
+//
+// +kubebuilder:validation:MinProperties=1
+type ContextMatcher struct {
+	// Context is matched against the name of the context.
+	//
+	// +optional
+	Context string `json:"context,omitempty"`
+
+	// Cluster is matched against the name of the cluster the context
+	// refers to.
+	//
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// User is matched against the name of the user the context refers
+	// to.
+	//
+	// +optional
+	User string `json:"user,omitempty"`
+}
+
+// ContextOverride customises the names and metadata used when exporting a
+// single context.
+type ContextOverride struct {
+	// Context is the name of the context in the kubeconfig.
+	//
+	// +required
+	Context string `json:"context"`
+
+	// Alias is the name given to the context in the exported kubeconfig.
+	// When set, it is also used in place of the context name when
+	// deriving the namespace and secret names.
+	//
+	// +optional
+	Alias string `json:"alias,omitempty"`
+
+	// Namespace is the namespace the secret is written to. When set, the
+	// namespace prefix is not applied.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Namespace string `json:"namespace,omitempty"`
+
+	// SecretName is the name of the kubeconfig secret. When set, the
+	// `-kubeconfig` suffix is not applied.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=253
+	SecretName string `json:"secretName,omitempty"`
+
+	// Labels are additional labels set on the namespace and secret.
+	//
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are additional annotations set on the namespace and
+	// secret.
+	//
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Provider selects the credential provider for this context instead
+	// of detecting it from the user, e.g. aws or client-cert. Set exec to
+	// copy the exec plugin of the user into the exported kubeconfig. The
+	// name is checked against the providers built into the operator, and
+	// an unknown name is reported on the context as UnsupportedProvider.
+	//
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// SecretFormat replaces the secret format from the Cluster spec for
+	// this context.
+	//
+	// +optional
+	SecretFormat *SecretFormat `json:"secretFormat,omitempty"`
+}
+
+// SecretFormat describes the layout of the data in a kubeconfig secret.
+type SecretFormat struct {
+	// Keys are the keys the kubeconfig is written under, as YAML.
+	// Defaults to `value`, which is the key expected by Flux.
+	//
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+
+	// JSONKey, if set, also writes the kubeconfig as JSON under this key.
+	//
+	// +optional
+	JSONKey string `json:"jsonKey,omitempty"`
+
+	// Split additionally writes the server address and each credential
+	// under its own key: `server`, `ca.crt`, `tls.crt`, `tls.key` and
+	// `token`. Keys for credentials the context does not use are omitted.
+	//
+	// +optional
+	Split bool `json:"split,omitempty"`
+
+	// ClusterAPI, when set, writes the secret in the format Cluster API
+	// expects: of type `cluster.x-k8s.io/secret`, labelled with
+	// `cluster.x-k8s.io/cluster-name` and with the kubeconfig under
+	// `value`.
+	//
+	// +optional
+	ClusterAPI *ClusterAPIFormat `json:"clusterAPI,omitempty"`
+}
+
+// ClusterAPIFormat configures Cluster API compatible kubeconfig secrets.
+//
+// The Cluster API cluster name is the secret name without the
+// `-kubeconfig` suffix, so a secretName override must keep that suffix.
+type ClusterAPIFormat struct {
+	// CreateCluster also creates a paused placeholder Cluster API
+	// `Cluster` in the same namespace so that Cluster API aware tools can
+	// discover it. Requires the Cluster API CRDs to be installed.
+	//
+	// +optional
+	CreateCluster bool `json:"createCluster,omitempty"`
+}
+
 type ClusterStatusEntry struct {
 	// Ready is true when the cluster is ready to accept requests.
 	Ready bool `json:"ready"`
 
+	// Reason is a machine readable explanation of the Ready state.
+	//
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable explanation of the Ready state.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time Ready or Reason changed.
+	//
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
 	// Endpoint is the endpoint of the cluster.
 	Endpoint string `json:"endpoint"`
 
 	// KubeConfig is the kubeconfig secret for the cluster.
 	KubeConfig string `json:"kubeConfig"`
 
+	// Provider is the credential provider used to export the context.
+	//
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
 	// LastUpdateTime is the last time the cluster was updated.
 	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+
+	// ExpirationTime is the time at which the credentials in the
+	// kubeconfig secret expire. It is only set for providers that issue
+	// short lived tokens.
+	//
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+
+	// Probe is the result of the last reachability check.
+	//
+	// +optional
+	Probe *ProbeResult `json:"probe,omitempty"`
+
+	// Facts are details collected from the cluster after a successful
+	// probe.
+	//
+	// +optional
+	Facts *ClusterFacts `json:"facts,omitempty"`
 }
 
 type ClusterStatusEntries map[string]ClusterStatusEntry
 
+// Categories of reachability failure.
+const (
+	// ProbeCategoryNone is set when the cluster was reached.
+	ProbeCategoryNone = "None"
+
+	// ProbeCategoryDNS is set when the server name could not be resolved.
+	ProbeCategoryDNS = "DNS"
+
+	// ProbeCategoryConnectionRefused is set when nothing is listening on
+	// the server address.
+	ProbeCategoryConnectionRefused = "ConnectionRefused"
+
+	// ProbeCategoryNetworkUnreachable is set when there is no route to the
+	// server address.
+	ProbeCategoryNetworkUnreachable = "NetworkUnreachable"
+
+	// ProbeCategoryTimeout is set when the server did not respond in time.
+	ProbeCategoryTimeout = "Timeout"
+
+	// ProbeCategoryTLS is set when the server certificate was rejected.
+	ProbeCategoryTLS = "TLS"
+
+	// ProbeCategoryUnauthorized is set when the credentials were rejected.
+	ProbeCategoryUnauthorized = "Unauthorized"
+
+	// ProbeCategoryForbidden is set when the credentials are valid but do
+	// not allow listing namespaces.
+	ProbeCategoryForbidden = "Forbidden"
+
+	// ProbeCategoryUnknown is set for any other failure.
+	ProbeCategoryUnknown = "Unknown"
+)
+
+// ClusterFacts describe a cluster reached through an exported kubeconfig.
+type ClusterFacts struct {
+	// KubernetesVersion is the git version of the API server.
+	//
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// Platform is the OS and architecture reported by the API server.
+	//
+	// +optional
+	Platform string `json:"platform,omitempty"`
+
+	// Nodes is the number of nodes in the cluster. It is not set when the
+	// exported credentials cannot list nodes.
+	//
+	// +optional
+	Nodes *int32 `json:"nodes,omitempty"`
+
+	// ReadyNodes is the number of nodes with a Ready condition of True.
+	//
+	// +optional
+	ReadyNodes *int32 `json:"readyNodes,omitempty"`
+
+	// APIGroups lists the well-known API groups installed in the cluster,
+	// such as fluxcd, Argo, cert-manager and Gateway API.
+	//
+	// +optional
+	APIGroups []string `json:"apiGroups,omitempty"`
+}
+
+// ProbeResult is the outcome of a reachability check against a cluster.
+type ProbeResult struct {
+	// Category classifies the failure, or is None when the cluster was
+	// reached.
+	//
+	// +kubebuilder:validation:Enum=None;DNS;ConnectionRefused;NetworkUnreachable;Timeout;TLS;Unauthorized;Forbidden;Unknown
+	Category string `json:"category"`
+
+	// Error is the error returned by the check.
+	//
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// Latency is the time taken to fetch /version from the server.
+	//
+	// +optional
+	Latency metav1.Duration `json:"latency,omitempty"`
+
+	// ServerVersion is the git version reported by the server's /version
+	// endpoint.
+	//
+	// +optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+}
+
+// PrunedResource is a namespace or secret that was removed because its
+// context disappeared from the kubeconfig.
+type PrunedResource struct {
+	// Kind is the kind of the object that was pruned.
+	Kind string `json:"kind"`
+
+	// Name is the name of the object that was pruned.
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the object that was pruned.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Context is the kubeconfig context the object was created for.
+	Context string `json:"context"`
+
+	// PrunedAt is the time the object was pruned.
+	PrunedAt metav1.Time `json:"prunedAt"`
+}
+
+const (
+	// ConflictKindContext is a conflicting context entry.
+	ConflictKindContext = "Context"
+
+	// ConflictKindCluster is a conflicting cluster entry.
+	ConflictKindCluster = "Cluster"
+
+	// ConflictKindUser is a conflicting user entry.
+	ConflictKindUser = "User"
+)
+
+// KubeConfigConflict is an entry defined differently in more than one
+// kubeconfig file.
+type KubeConfigConflict struct {
+	// Kind is the kind of entry, one of Context, Cluster or User.
+	Kind string `json:"kind"`
+
+	// Name is the name of the entry.
+	Name string `json:"name"`
+
+	// File is the file the entry was taken from.
+	File string `json:"file"`
+
+	// Ignored lists the files whose definition of the entry was ignored.
+	Ignored []string `json:"ignored"`
+}
+
 // ClusterStatus defines the observed state of Cluster.
 type ClusterStatus struct {
+	// ObservedGeneration is the last generation of the Cluster that was
+	// reconciled.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest observations of the Cluster.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Clusters ClusterStatusEntries `json:"clusters"`
 
@@ -108,10 +706,32 @@ type ClusterStatus struct {
 	//
 	// +optional
 	DeletionRules []string `json:"deletionRules,omitempty"`
+
+	// Pruned lists the most recent namespaces and secrets removed because
+	// their context is no longer in the kubeconfig.
+	//
+	// +optional
+	Pruned []PrunedResource `json:"pruned,omitempty"`
+
+	// Conflicts lists entries defined differently in more than one
+	// kubeconfig file.
+	//
+	// +optional
+	Conflicts []KubeConfigConflict `json:"conflicts,omitempty"`
+
+	// ClusterAPIClusters is true while placeholder Cluster API Clusters
+	// created for the Cluster may exist. They are only looked up while
+	// this is set or `clusterAPI.createCluster` is enabled.
+	//
+	// +optional
+	ClusterAPIClusters bool `json:"clusterAPIClusters,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Cluster is the Schema for the clusters API.
 type Cluster struct {
@@ -21,9 +21,32 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDSink) DeepCopyInto(out *ArgoCDSink) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDSink.
+func (in *ArgoCDSink) DeepCopy() *ArgoCDSink {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -51,6 +74,51 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAPIFormat) DeepCopyInto(out *ClusterAPIFormat) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAPIFormat.
+func (in *ClusterAPIFormat) DeepCopy() *ClusterAPIFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAPIFormat)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterFacts) DeepCopyInto(out *ClusterFacts) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ReadyNodes != nil {
+		in, out := &in.ReadyNodes, &out.ReadyNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.APIGroups != nil {
+		in, out := &in.APIGroups, &out.APIGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterFacts.
+func (in *ClusterFacts) DeepCopy() *ClusterFacts {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterFacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -91,7 +159,50 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ArgoCD != nil {
+		in, out := &in.ArgoCD, &out.ArgoCD
+		*out = new(ArgoCDSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Contexts != nil {
+		in, out := &in.Contexts, &out.Contexts
+		*out = new(ContextSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPathMappings != nil {
+		in, out := &in.HostPathMappings, &out.HostPathMappings
+		*out = make([]HostPathMapping, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeConfigPaths != nil {
+		in, out := &in.KubeConfigPaths, &out.KubeConfigPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KubeConfigSource != nil {
+		in, out := &in.KubeConfigSource, &out.KubeConfigSource
+		*out = new(KubeConfigSource)
+		**out = **in
+	}
+	if in.Overrides != nil {
+		in, out := &in.Overrides, &out.Overrides
+		*out = make([]ContextOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Prune != nil {
+		in, out := &in.Prune, &out.Prune
+		*out = new(bool)
+		**out = **in
+	}
+	out.PruneGracePeriod = in.PruneGracePeriod
 	out.ReconcileInterval = in.ReconcileInterval
+	if in.SecretFormat != nil {
+		in, out := &in.SecretFormat, &out.SecretFormat
+		*out = new(SecretFormat)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -107,6 +218,13 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make(ClusterStatusEntries, len(*in))
@@ -124,6 +242,20 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Pruned != nil {
+		in, out := &in.Pruned, &out.Pruned
+		*out = make([]PrunedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]KubeConfigConflict, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -160,7 +292,22 @@ func (in ClusterStatusEntries) DeepCopy() ClusterStatusEntries {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatusEntry) DeepCopyInto(out *ClusterStatusEntry) {
 	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Probe != nil {
+		in, out := &in.Probe, &out.Probe
+		*out = new(ProbeResult)
+		**out = **in
+	}
+	if in.Facts != nil {
+		in, out := &in.Facts, &out.Facts
+		*out = new(ClusterFacts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatusEntry.
@@ -172,3 +319,184 @@ func (in *ClusterStatusEntry) DeepCopy() *ClusterStatusEntry {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextMatcher) DeepCopyInto(out *ContextMatcher) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextMatcher.
+func (in *ContextMatcher) DeepCopy() *ContextMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextOverride) DeepCopyInto(out *ContextOverride) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretFormat != nil {
+		in, out := &in.SecretFormat, &out.SecretFormat
+		*out = new(SecretFormat)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextOverride.
+func (in *ContextOverride) DeepCopy() *ContextOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextSelector) DeepCopyInto(out *ContextSelector) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]ContextMatcher, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]ContextMatcher, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextSelector.
+func (in *ContextSelector) DeepCopy() *ContextSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPathMapping) DeepCopyInto(out *HostPathMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPathMapping.
+func (in *HostPathMapping) DeepCopy() *HostPathMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPathMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeConfigConflict) DeepCopyInto(out *KubeConfigConflict) {
+	*out = *in
+	if in.Ignored != nil {
+		in, out := &in.Ignored, &out.Ignored
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeConfigConflict.
+func (in *KubeConfigConflict) DeepCopy() *KubeConfigConflict {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeConfigConflict)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeConfigSource) DeepCopyInto(out *KubeConfigSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeConfigSource.
+func (in *KubeConfigSource) DeepCopy() *KubeConfigSource {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeConfigSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbeResult) DeepCopyInto(out *ProbeResult) {
+	*out = *in
+	out.Latency = in.Latency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeResult.
+func (in *ProbeResult) DeepCopy() *ProbeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrunedResource) DeepCopyInto(out *PrunedResource) {
+	*out = *in
+	in.PrunedAt.DeepCopyInto(&out.PrunedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrunedResource.
+func (in *PrunedResource) DeepCopy() *PrunedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(PrunedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretFormat) DeepCopyInto(out *SecretFormat) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterAPI != nil {
+		in, out := &in.ClusterAPI, &out.ClusterAPI
+		*out = new(ClusterAPIFormat)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretFormat.
+func (in *SecretFormat) DeepCopy() *SecretFormat {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretFormat)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -18,24 +18,50 @@ package controller
 
 import (
 	"context"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+	"github.com/mproffitt/kubeconfig-operator/internal/filewatcher"
 	kubeconfig "github.com/mproffitt/kubeconfig-operator/internal/kubeconfig"
 )
 
+// ClusterFinalizer is added to every Cluster so that the namespaces and
+// secrets created for it can be removed before the Cluster is deleted.
+const ClusterFinalizer = "kubeconfig.choclab.net/finalizer"
+
 // ClusterReconciler reconciles a Cluster object
 type ClusterReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// Recorder emits events on the Cluster. Events are not recorded when
+	// it is nil.
+	Recorder record.EventRecorder
+
+	// Watcher triggers reconciliation when a kubeconfig file changes. One
+	// is created by SetupWithManager if not set.
+	Watcher *filewatcher.Watcher
 }
 
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubeconfig.choclab.net,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=kubeconfig.choclab.net,resources=clusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=kubeconfig.choclab.net,resources=clusters/finalizers,verbs=update
@@ -54,6 +80,9 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	var cluster kccnv1alpha1.Cluster
 	if err := r.Get(ctx, req.NamespacedName, &cluster); err != nil {
+		if apierrors.IsNotFound(err) && r.Watcher != nil {
+			r.Watcher.Forget(req.NamespacedName)
+		}
 		log.Error(err, "unable to fetch Cluster")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -61,7 +90,24 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	metadata := cluster.GetObjectMeta()
 	if metadata.GetDeletionTimestamp() != nil {
 		log.Info("Cluster is being deleted", "name", metadata.GetName())
-		return ctrl.Result{}, nil
+		if r.Watcher != nil {
+			r.Watcher.Forget(req.NamespacedName)
+		}
+		return r.reconcileDelete(ctx, &cluster)
+	}
+
+	if r.Watcher != nil {
+		if err := r.Watcher.Watch(req.NamespacedName, kubeconfig.Paths(&cluster)); err != nil {
+			log.Error(err, "unable to watch kubeconfig, relying on reconcileInterval")
+		}
+	}
+
+	if !controllerutil.ContainsFinalizer(&cluster, ClusterFinalizer) {
+		controllerutil.AddFinalizer(&cluster, ClusterFinalizer)
+		if err := r.Update(ctx, &cluster); err != nil {
+			log.Error(err, "unable to add finalizer to Cluster")
+			return ctrl.Result{}, err
+		}
 	}
 
 	if cluster.Spec.Suspend {
@@ -71,17 +117,29 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 	log.Info("Reconciling Cluster", "name", metadata.GetName())
 
-	manager := kubeconfig.NewManager(ctx, r.Client, &cluster)
+	manager := kubeconfig.NewManager(ctx, r.Client, r.Recorder, &cluster)
 
 	statuses, err := manager.ReconcileKubeconfig()
 	if err != nil {
 		log.Error(err, "unable to reconcile kubeconfig", "name", metadata.GetName())
+		setLoadFailedConditions(&cluster, err)
+		if r.Recorder != nil {
+			r.Recorder.Event(&cluster, corev1.EventTypeWarning, kccnv1alpha1.ReasonLoadFailed, err.Error())
+		}
+		if statusErr := r.Status().Update(ctx, &cluster); statusErr != nil {
+			log.Error(statusErr, "unable to update Cluster status")
+		}
 		return ctrl.Result{}, err
 	}
 
 	cluster.Status.Clusters = statuses.ClusterStatus
 	cluster.Status.FirewallRules = statuses.FirewallRules
 	cluster.Status.DeletionRules = statuses.DeletionRules
+	cluster.Status.Pruned = statuses.Pruned
+	cluster.Status.Conflicts = statuses.Conflicts
+	cluster.Status.ClusterAPIClusters = statuses.ClusterAPIClusters
+	cluster.Status.ObservedGeneration = cluster.Generation
+	setConditions(&cluster)
 
 	if err := r.Status().Update(ctx, &cluster); err != nil {
 		log.Error(err, "unable to update Cluster status")
@@ -89,14 +147,116 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	return ctrl.Result{
-		RequeueAfter: cluster.Spec.ReconcileInterval.Duration,
+		RequeueAfter: requeueAfter(cluster.Spec.ReconcileInterval.Duration, statuses.NextRefresh),
 	}, nil
 }
 
+// requeueAfter returns the reconcile interval, shortened if short lived
+// credentials need refreshing before the next interval elapses.
+func requeueAfter(interval time.Duration, nextRefresh time.Time) time.Duration {
+	if nextRefresh.IsZero() {
+		return interval
+	}
+
+	until := time.Until(nextRefresh)
+	if until < time.Second {
+		until = time.Second
+	}
+
+	if interval == 0 || until < interval {
+		return until
+	}
+
+	return interval
+}
+
+// reconcileDelete removes the namespaces and secrets created for the
+// Cluster, honouring the deletion policy, before releasing the finalizer.
+func (r *ClusterReconciler) reconcileDelete(
+	ctx context.Context, cluster *kccnv1alpha1.Cluster,
+) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(cluster, ClusterFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	manager := kubeconfig.NewManager(ctx, r.Client, r.Recorder, cluster)
+	if err := manager.Teardown(); err != nil {
+		log.Error(err, "unable to tear down Cluster", "name", cluster.GetName())
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(cluster, ClusterFinalizer)
+	if err := r.Update(ctx, cluster); err != nil {
+		log.Error(err, "unable to remove finalizer from Cluster")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Watcher == nil {
+		watcher, err := filewatcher.New()
+		if err != nil {
+			return err
+		}
+		r.Watcher = watcher
+	}
+
+	if err := mgr.Add(r.Watcher); err != nil {
+		return err
+	}
+
+	// Only labelled sources are watched. Together with the cache options
+	// set in main this keeps every other Secret and ConfigMap out of the
+	// cache.
+	sources := builder.WithPredicates(predicate.NewPredicateFuncs(isKubeConfigSource))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&kccnv1alpha1.Cluster{}).
+		WatchesRawSource(source.Channel(r.Watcher.Events(), &handler.EnqueueRequestForObject{})).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(
+			r.clustersForSource(kccnv1alpha1.KubeConfigSourceSecret),
+		), sources).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(
+			r.clustersForSource(kccnv1alpha1.KubeConfigSourceConfigMap),
+		), sources).
 		Named("cluster").
 		Complete(r)
 }
+
+// isKubeConfigSource returns true for a Secret or ConfigMap labelled as
+// a kubeConfigSource.
+func isKubeConfigSource(obj client.Object) bool {
+	return obj.GetLabels()[kubeconfig.LabelKubeConfigSource] == "true"
+}
+
+// clustersForSource maps a Secret or ConfigMap to the Clusters that read
+// their kubeconfig from it.
+func (r *ClusterReconciler) clustersForSource(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		clusters := &kccnv1alpha1.ClusterList{}
+		// Sources are always read from the namespace of the Cluster
+		if err := r.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list clusters for kubeconfig source")
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range clusters.Items {
+			cluster := &clusters.Items[i]
+			source := cluster.Spec.KubeConfigSource
+			if source == nil || source.Kind != kind || source.Name != obj.GetName() {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(cluster),
+			})
+		}
+
+		return requests
+	}
+}
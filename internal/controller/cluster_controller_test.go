@@ -18,16 +18,24 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	kubeconfigchoclabnetv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig"
 )
 
 var _ = Describe("Cluster Controller", func() {
@@ -42,7 +50,18 @@ var _ = Describe("Cluster Controller", func() {
 		}
 		cluster := &kubeconfigchoclabnetv1alpha1.Cluster{}
 
+		// envtest has no namespace controller, so a namespace removed when
+		// the resource is deleted never goes away. Each spec gets its own
+		// context to avoid reusing a terminating namespace.
+		var (
+			contexts       int
+			kubeConfigPath string
+		)
+
 		BeforeEach(func() {
+			contexts++
+			kubeConfigPath = writeKubeconfig(fmt.Sprintf("envtest-%d", contexts))
+
 			By("creating the custom resource for the Kind Cluster")
 			err := k8sClient.Get(ctx, typeNamespacedName, cluster)
 			if err != nil && errors.IsNotFound(err) {
@@ -51,7 +70,9 @@ var _ = Describe("Cluster Controller", func() {
 						Name:      resourceName,
 						Namespace: "default",
 					},
-					// TODO(user): Specify other spec details if needed.
+					Spec: kubeconfigchoclabnetv1alpha1.ClusterSpec{
+						KubeConfigPath: kubeConfigPath,
+					},
 				}
 				Expect(k8sClient.Create(ctx, resource)).To(Succeed())
 			}
@@ -65,6 +86,19 @@ var _ = Describe("Cluster Controller", func() {
 
 			By("Cleanup the specific resource instance Cluster")
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			By("Reconciling the deletion to release the finalizer")
+			controllerReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = k8sClient.Get(ctx, typeNamespacedName, resource)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
@@ -80,5 +114,203 @@ var _ = Describe("Cluster Controller", func() {
 			// TODO(user): Add more specific assertions depending on your controller's reconciliation logic.
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
+
+		It("should add the finalizer to the resource", func() {
+			controllerReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &kubeconfigchoclabnetv1alpha1.Cluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Finalizers).To(ContainElement(ClusterFinalizer))
+		})
+
+		It("should set status conditions", func() {
+			controllerReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resource := &kubeconfigchoclabnetv1alpha1.Cluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ObservedGeneration).To(Equal(resource.Generation))
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions,
+				kubeconfigchoclabnetv1alpha1.ConditionKubeconfigLoaded)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(resource.Status.Conditions,
+				kubeconfigchoclabnetv1alpha1.ConditionDegraded)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(resource.Status.Conditions,
+				kubeconfigchoclabnetv1alpha1.ConditionReady)).To(BeTrue())
+		})
+
+		It("should prune owned namespaces whose context is gone", func() {
+			By("creating a namespace for a context that is not in the kubeconfig")
+			stale := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-kind-gone",
+					Labels: map[string]string{
+						kubeconfig.LabelManagedBy:        kubeconfig.LabelManagedByValue,
+						kubeconfig.LabelClusterName:      resourceName,
+						kubeconfig.LabelClusterNamespace: "default",
+					},
+					Annotations: map[string]string{
+						kubeconfig.AnnotationContext: "kind-gone",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, stale)).To(Succeed())
+
+			controllerReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: stale.Name}, ns)).To(Succeed())
+			Expect(ns.DeletionTimestamp).NotTo(BeNil())
+
+			resource := &kubeconfigchoclabnetv1alpha1.Cluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.Pruned).To(ContainElement(HaveField("Name", stale.Name)))
+		})
+
+		It("should remove owned namespaces on deletion", func() {
+			By("creating a namespace owned by the resource")
+			owned := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster-owned-by-test-resource",
+					Labels: map[string]string{
+						kubeconfig.LabelManagedBy:        kubeconfig.LabelManagedByValue,
+						kubeconfig.LabelClusterName:      resourceName,
+						kubeconfig.LabelClusterNamespace: "default",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, owned)).To(Succeed())
+
+			controllerReconciler := &ClusterReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("deleting the resource")
+			resource := &kubeconfigchoclabnetv1alpha1.Cluster{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// envtest has no namespace controller so the namespace
+			// remains in a terminating state rather than disappearing.
+			ns := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: owned.Name}, ns)).To(Succeed())
+			Expect(ns.DeletionTimestamp).NotTo(BeNil())
+
+			By("recreating the resource for the AfterEach cleanup")
+			Expect(k8sClient.Create(ctx, &kubeconfigchoclabnetv1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: kubeconfigchoclabnetv1alpha1.ClusterSpec{
+					KubeConfigPath: kubeConfigPath,
+				},
+			})).To(Succeed())
+		})
+	})
+})
+
+// writeKubeconfig writes a kubeconfig with a single context that reaches
+// the envtest API server using its admin client certificate.
+func writeKubeconfig(context string) string {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[context] = &clientcmdapi.Cluster{
+		Server:                   cfg.Host,
+		CertificateAuthorityData: cfg.CAData,
+	}
+	config.AuthInfos[context] = &clientcmdapi.AuthInfo{
+		ClientCertificateData: cfg.CertData,
+		ClientKeyData:         cfg.KeyData,
+	}
+	config.Contexts[context] = &clientcmdapi.Context{
+		Cluster:  context,
+		AuthInfo: context,
+	}
+	config.CurrentContext = context
+
+	path := filepath.Join(GinkgoT().TempDir(), "config")
+	Expect(clientcmd.WriteToFile(*config, path)).To(Succeed())
+	return path
+}
+
+var _ = Describe("requeueAfter", func() {
+	It("should return the interval when nothing needs refreshing", func() {
+		Expect(requeueAfter(30*time.Second, time.Time{})).To(Equal(30 * time.Second))
+	})
+
+	It("should requeue before credentials need refreshing", func() {
+		next := time.Now().Add(10 * time.Second)
+		Expect(requeueAfter(time.Minute, next)).To(BeNumerically("<=", 10*time.Second))
+	})
+
+	It("should not requeue immediately when a refresh is overdue", func() {
+		next := time.Now().Add(-time.Minute)
+		Expect(requeueAfter(time.Minute, next)).To(Equal(time.Second))
+	})
+})
+
+var _ = Describe("setLoadFailedConditions", func() {
+	It("should mark the resource as degraded and not ready", func() {
+		cluster := &kubeconfigchoclabnetv1alpha1.Cluster{}
+		cluster.Generation = 2
+		setConditions(cluster)
+
+		setLoadFailedConditions(cluster, fmt.Errorf("no kubeconfig configured"))
+
+		for _, conditionType := range []string{
+			kubeconfigchoclabnetv1alpha1.ConditionKubeconfigLoaded,
+			kubeconfigchoclabnetv1alpha1.ConditionDegraded,
+			kubeconfigchoclabnetv1alpha1.ConditionReady,
+		} {
+			condition := meta.FindStatusCondition(cluster.Status.Conditions, conditionType)
+			Expect(condition).NotTo(BeNil(), conditionType)
+			Expect(condition.Reason).To(Equal(kubeconfigchoclabnetv1alpha1.ReasonLoadFailed))
+			Expect(condition.Message).To(Equal("no kubeconfig configured"))
+			Expect(condition.ObservedGeneration).To(Equal(int64(2)))
+		}
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			kubeconfigchoclabnetv1alpha1.ConditionDegraded)).To(BeTrue())
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			kubeconfigchoclabnetv1alpha1.ConditionReady)).To(BeTrue())
+	})
+})
+
+var _ = Describe("isKubeConfigSource", func() {
+	It("should only match objects labelled as a kubeconfig source", func() {
+		secret := &corev1.Secret{}
+		Expect(isKubeConfigSource(secret)).To(BeFalse())
+
+		secret.Labels = map[string]string{kubeconfig.LabelKubeConfigSource: "true"}
+		Expect(isKubeConfigSource(secret)).To(BeTrue())
 	})
 })
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// setConditions sets the Ready, KubeconfigLoaded and Degraded conditions
+// from the per context status following a successful reconciliation.
+func setConditions(cluster *kccnv1alpha1.Cluster) {
+	generation := cluster.Generation
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionKubeconfigLoaded,
+		Status:             metav1.ConditionTrue,
+		Reason:             kccnv1alpha1.ReasonLoaded,
+		Message:            fmt.Sprintf("found %d contexts", len(cluster.Status.Clusters)),
+		ObservedGeneration: generation,
+	})
+
+	notReady := make([]string, 0)
+	for name, entry := range cluster.Status.Clusters {
+		if !entry.Ready {
+			notReady = append(notReady, name)
+		}
+	}
+	sort.Strings(notReady)
+
+	if len(notReady) > 0 {
+		message := fmt.Sprintf("%d of %d contexts not ready: %s",
+			len(notReady), len(cluster.Status.Clusters), strings.Join(notReady, ", "))
+
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               kccnv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             kccnv1alpha1.ReasonContextsNotReady,
+			Message:            message,
+			ObservedGeneration: generation,
+		})
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:               kccnv1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             kccnv1alpha1.ReasonContextsNotReady,
+			Message:            message,
+			ObservedGeneration: generation,
+		})
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             kccnv1alpha1.ReasonContextsReady,
+		Message:            "all contexts are ready",
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             kccnv1alpha1.ReasonReconciled,
+		Message:            fmt.Sprintf("%d contexts ready", len(cluster.Status.Clusters)),
+		ObservedGeneration: generation,
+	})
+}
+
+// setLoadFailedConditions marks the Cluster as degraded and not ready when
+// the kubeconfig could not be loaded.
+func setLoadFailedConditions(cluster *kccnv1alpha1.Cluster, err error) {
+	generation := cluster.Generation
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionKubeconfigLoaded,
+		Status:             metav1.ConditionFalse,
+		Reason:             kccnv1alpha1.ReasonLoadFailed,
+		Message:            err.Error(),
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             kccnv1alpha1.ReasonLoadFailed,
+		Message:            err.Error(),
+		ObservedGeneration: generation,
+	})
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               kccnv1alpha1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             kccnv1alpha1.ReasonLoadFailed,
+		Message:            err.Error(),
+		ObservedGeneration: generation,
+	})
+}
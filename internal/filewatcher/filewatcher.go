@@ -0,0 +1,267 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filewatcher
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// Watcher watches the kubeconfig files used by Cluster objects and
+// emits a generic event for each Cluster whose file changes.
+//
+// The parent directory is watched rather than the file itself, as tools
+// such as `kubectl config` replace the file with an atomic rename which
+// drops a watch held on the file.
+type Watcher struct {
+	watcher *fsnotify.Watcher
+	events  chan event.GenericEvent
+
+	mu       sync.Mutex
+	files    map[string]map[types.NamespacedName]struct{}
+	dirs     map[string]int
+	clusters map[types.NamespacedName][]string
+}
+
+// New creates a Watcher. It must be started before events
+// are delivered.
+func New() (*Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create file watcher")
+	}
+
+	return &Watcher{
+		watcher:  watcher,
+		events:   make(chan event.GenericEvent),
+		files:    make(map[string]map[types.NamespacedName]struct{}),
+		dirs:     make(map[string]int),
+		clusters: make(map[types.NamespacedName][]string),
+	}, nil
+}
+
+// Events returns the channel Cluster events are delivered on.
+func (w *Watcher) Events() <-chan event.GenericEvent {
+	return w.events
+}
+
+// Watch sets the files watched on behalf of a Cluster, replacing any
+// previously watched files. Paths may be globs, in which case files
+// created in the directory that match the glob are picked up too. Only
+// the file name may be a glob; a glob in the directory is rejected.
+//
+// Files that were already watched keep their watch, so a change made
+// while the Cluster is being reconciled is not missed.
+func (w *Watcher) Watch(cluster types.NamespacedName, paths []string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wanted := []string{}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+
+		path = filepath.Clean(path)
+		if dir := filepath.Dir(path); hasMeta(dir) {
+			return errors.Errorf("cannot watch %s, globs are only supported in the file name", path)
+		}
+		if !seen[path] {
+			seen[path] = true
+			wanted = append(wanted, path)
+		}
+	}
+
+	current := make(map[string]bool)
+	for _, path := range w.clusters[cluster] {
+		current[path] = true
+	}
+	if len(current) == len(wanted) && allIn(wanted, current) {
+		return nil
+	}
+
+	// New paths are added before stale ones are removed so that a
+	// directory shared by both is never unwatched.
+	watched := []string{}
+	for _, path := range wanted {
+		if !current[path] {
+			if err := w.add(cluster, path); err != nil {
+				// Everything not yet handled is still watched.
+				for remaining := range current {
+					watched = append(watched, remaining)
+				}
+				w.clusters[cluster] = watched
+				return err
+			}
+		}
+		watched = append(watched, path)
+		delete(current, path)
+	}
+
+	for path := range current {
+		w.remove(cluster, path)
+	}
+	w.clusters[cluster] = watched
+
+	return nil
+}
+
+// Forget stops watching the files of a Cluster.
+func (w *Watcher) Forget(cluster types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, path := range w.clusters[cluster] {
+		w.remove(cluster, path)
+	}
+	delete(w.clusters, cluster)
+}
+
+// add watches path on behalf of cluster.
+func (w *Watcher) add(cluster types.NamespacedName, path string) error {
+	dir := filepath.Dir(path)
+	if w.dirs[dir] == 0 {
+		if err := w.watcher.Add(dir); err != nil {
+			return errors.Wrapf(err, "failed to watch %s", dir)
+		}
+	}
+	w.dirs[dir]++
+
+	if _, ok := w.files[path]; !ok {
+		w.files[path] = make(map[types.NamespacedName]struct{})
+	}
+	w.files[path][cluster] = struct{}{}
+
+	return nil
+}
+
+// remove stops watching path on behalf of cluster, dropping the watch on
+// its directory once no other path needs it.
+func (w *Watcher) remove(cluster types.NamespacedName, path string) {
+	delete(w.files[path], cluster)
+	if len(w.files[path]) == 0 {
+		delete(w.files, path)
+	}
+
+	dir := filepath.Dir(path)
+	w.dirs[dir]--
+	if w.dirs[dir] <= 0 {
+		delete(w.dirs, dir)
+		_ = w.watcher.Remove(dir)
+	}
+}
+
+// allIn returns true if every path is in set.
+func allIn(paths []string, set map[string]bool) bool {
+	for _, path := range paths {
+		if !set[path] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasMeta returns true if path contains any of the characters
+// filepath.Match treats as special.
+func hasMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[\`)
+}
+
+// clustersFor returns the Clusters affected by a change to name.
+//
+// Files mounted from a Secret or ConfigMap are updated by swapping the
+// `..data` symlink, so any change to a `..` entry affects every file in
+// the directory.
+func (w *Watcher) clustersFor(name string) []types.NamespacedName {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	name = filepath.Clean(name)
+	dir := filepath.Dir(name)
+	mounted := strings.HasPrefix(filepath.Base(name), "..")
+
+	seen := make(map[types.NamespacedName]struct{})
+	for path, clusters := range w.files {
+		matched, _ := filepath.Match(path, name)
+		if !matched && (!mounted || filepath.Dir(path) != dir) {
+			continue
+		}
+		for cluster := range clusters {
+			seen[cluster] = struct{}{}
+		}
+	}
+
+	clusters := make([]types.NamespacedName, 0, len(seen))
+	for cluster := range seen {
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// Start implements manager.Runnable.
+func (w *Watcher) Start(ctx context.Context) error {
+	log := log.FromContext(ctx).WithName("filewatcher")
+	defer func() {
+		_ = w.watcher.Close()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Error(err, "error watching kubeconfig files")
+		case ev, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+
+			for _, cluster := range w.clustersFor(ev.Name) {
+				log.V(1).Info("kubeconfig changed", "file", ev.Name, "cluster", cluster)
+				obj := &kccnv1alpha1.Cluster{}
+				obj.SetNamespace(cluster.Namespace)
+				obj.SetName(cluster.Name)
+
+				select {
+				case w.events <- event.GenericEvent{Object: client.Object(obj)}:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		}
+	}
+}
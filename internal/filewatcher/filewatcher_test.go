@@ -0,0 +1,134 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package filewatcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+var _ = Describe("Watcher", func() {
+	var (
+		watcher *Watcher
+		cancel  context.CancelFunc
+		dir     string
+		path    string
+		key     = types.NamespacedName{Name: "sample", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		var err error
+		watcher, err = New()
+		Expect(err).NotTo(HaveOccurred())
+
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, "config")
+		Expect(os.WriteFile(path, []byte("a"), 0o600)).To(Succeed())
+
+		var watchCtx context.Context
+		watchCtx, cancel = context.WithCancel(context.Background())
+		go func() {
+			defer GinkgoRecover()
+			Expect(watcher.Start(watchCtx)).To(Succeed())
+		}()
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	receive := func() types.NamespacedName {
+		var ev event.GenericEvent
+		Eventually(watcher.Events(), time.Second).Should(Receive(&ev))
+		return types.NamespacedName{Name: ev.Object.GetName(), Namespace: ev.Object.GetNamespace()}
+	}
+
+	It("should emit an event when the file is written", func() {
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		Expect(os.WriteFile(path, []byte("b"), 0o600)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+	})
+
+	It("should emit an event when the file is replaced by a rename", func() {
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+
+		tmp := filepath.Join(dir, "config.tmp")
+		Expect(os.WriteFile(tmp, []byte("b"), 0o600)).To(Succeed())
+		Expect(os.Rename(tmp, path)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+
+		// The watch survives the rename.
+		Expect(os.WriteFile(path, []byte("c"), 0o600)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+	})
+
+	It("should ignore other files in the directory", func() {
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "other"), []byte("b"), 0o600)).To(Succeed())
+		Consistently(watcher.Events(), 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should emit an event for new files matching a glob", func() {
+		Expect(watcher.Watch(key, []string{filepath.Join(dir, "*.yaml")})).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "new.yaml"), []byte("b"), 0o600)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+	})
+
+	It("should stop emitting events once forgotten", func() {
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		watcher.Forget(key)
+		Expect(os.WriteFile(path, []byte("b"), 0o600)).To(Succeed())
+		Consistently(watcher.Events(), 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should keep the watch when the paths are unchanged", func() {
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		Expect(watcher.dirs).To(HaveKeyWithValue(dir, 1))
+
+		Expect(os.WriteFile(path, []byte("b"), 0o600)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+	})
+
+	It("should only change the paths that differ", func() {
+		other := filepath.Join(dir, "other")
+		Expect(watcher.Watch(key, []string{path})).To(Succeed())
+		Expect(watcher.Watch(key, []string{other, path})).To(Succeed())
+		Expect(watcher.clusters[key]).To(ConsistOf(path, other))
+		Expect(watcher.dirs).To(HaveKeyWithValue(dir, 2))
+
+		Expect(watcher.Watch(key, []string{other})).To(Succeed())
+		Expect(watcher.files).NotTo(HaveKey(path))
+		Expect(watcher.dirs).To(HaveKeyWithValue(dir, 1))
+
+		Expect(os.WriteFile(other, []byte("b"), 0o600)).To(Succeed())
+		Expect(receive()).To(Equal(key))
+	})
+
+	It("should reject globs in the directory", func() {
+		err := watcher.Watch(key, []string{filepath.Join(dir, "*", "*.yaml")})
+		Expect(err).To(MatchError(ContainSubstring("globs are only supported in the file name")))
+		Expect(watcher.dirs).To(BeEmpty())
+	})
+})
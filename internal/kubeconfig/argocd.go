@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// LabelArgoCDSecretType marks a secret as an Argo CD cluster secret.
+	LabelArgoCDSecretType = "argocd.argoproj.io/secret-type"
+
+	// DefaultArgoCDNamespace is used when the sink does not give a
+	// namespace.
+	DefaultArgoCDNamespace = "argocd"
+)
+
+// argoCDClusterConfig is the `config` key of an Argo CD cluster secret.
+type argoCDClusterConfig struct {
+	BearerToken     string                `json:"bearerToken,omitempty"`
+	Username        string                `json:"username,omitempty"`
+	Password        string                `json:"password,omitempty"`
+	TLSClientConfig argoCDTLSClientConfig `json:"tlsClientConfig"`
+
+	ExecProviderConfig *argoCDExecProviderConfig `json:"execProviderConfig,omitempty"`
+}
+
+type argoCDTLSClientConfig struct {
+	Insecure   bool   `json:"insecure"`
+	ServerName string `json:"serverName,omitempty"`
+	CAData     []byte `json:"caData,omitempty"`
+	CertData   []byte `json:"certData,omitempty"`
+	KeyData    []byte `json:"keyData,omitempty"`
+}
+
+type argoCDExecProviderConfig struct {
+	Command     string            `json:"command"`
+	Args        []string          `json:"args,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	APIVersion  string            `json:"apiVersion,omitempty"`
+	InstallHint string            `json:"installHint,omitempty"`
+}
+
+// createArgoCDSecretForCluster writes the context as an Argo CD cluster
+// secret, if the Argo CD sink is enabled. The expiry of the credentials
+// held in the secret is returned.
+func (m *Manager) createArgoCDSecretForCluster(t target, details *api.Config, expiry time.Time) (time.Time, error) {
+	sink := m.cluster.Spec.ArgoCD
+	if sink == nil {
+		return time.Time{}, nil
+	}
+
+	key := m.argoCDSecretKey(t)
+	data, err := argoCDSecretData(t.name, details, sink.Project)
+	if err != nil {
+		return expiry, err
+	}
+
+	hash := contentHash(data)
+	if !expiry.IsZero() {
+		stable, err := argoCDSecretData(t.name, withoutTokens(details), sink.Project)
+		if err != nil {
+			return expiry, err
+		}
+		hash = contentHash(stable)
+	}
+
+	labels := map[string]string{}
+	for k, v := range sink.Labels {
+		labels[k] = v
+	}
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	labels[LabelArgoCDSecretType] = "cluster"
+
+	return m.applySecret(desiredSecret{
+		namespace:   key.Namespace,
+		name:        key.Name,
+		labels:      labels,
+		annotations: t.annotations,
+		data:        data,
+		hash:        hash,
+		expiry:      expiry,
+	})
+}
+
+// argoCDSecretKey returns where the Argo CD cluster secret for a target is
+// written.
+func (m *Manager) argoCDSecretKey(t target) client.ObjectKey {
+	namespace := m.cluster.Spec.ArgoCD.Namespace
+	if namespace == "" {
+		namespace = DefaultArgoCDNamespace
+	}
+
+	return client.ObjectKey{Namespace: namespace, Name: "cluster-" + sanitizeName(t.name)}
+}
+
+// argoCDSecretData converts the current context of details into the keys of
+// an Argo CD cluster secret.
+func argoCDSecretData(name string, details *api.Config, project string) (map[string][]byte, error) {
+	ctx, ok := details.Contexts[details.CurrentContext]
+	if !ok {
+		return nil, errors.New("current context not found")
+	}
+
+	cluster, ok := details.Clusters[ctx.Cluster]
+	if !ok {
+		return nil, errors.New("cluster not found")
+	}
+
+	config := argoCDClusterConfig{
+		TLSClientConfig: argoCDTLSClientConfig{
+			Insecure:   cluster.InsecureSkipTLSVerify,
+			ServerName: cluster.TLSServerName,
+			CAData:     cluster.CertificateAuthorityData,
+		},
+	}
+
+	if authInfo, ok := details.AuthInfos[ctx.AuthInfo]; ok {
+		config.BearerToken = authInfo.Token
+		config.Username = authInfo.Username
+		config.Password = authInfo.Password
+		config.TLSClientConfig.CertData = authInfo.ClientCertificateData
+		config.TLSClientConfig.KeyData = authInfo.ClientKeyData
+
+		if exec := authInfo.Exec; exec != nil {
+			config.ExecProviderConfig = &argoCDExecProviderConfig{
+				Command:     exec.Command,
+				Args:        exec.Args,
+				APIVersion:  exec.APIVersion,
+				InstallHint: exec.InstallHint,
+			}
+			if len(exec.Env) > 0 {
+				config.ExecProviderConfig.Env = make(map[string]string, len(exec.Env))
+				for _, env := range exec.Env {
+					config.ExecProviderConfig.Env[env.Name] = env.Value
+				}
+			}
+		}
+	}
+
+	content, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal argo cd cluster config")
+	}
+
+	data := map[string][]byte{
+		"name":   []byte(name),
+		"server": []byte(cluster.Server),
+		"config": content,
+	}
+	if project != "" {
+		data["project"] = []byte(project)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var _ = ginkgo.Describe("argoCDSecretData", func() {
+	ginkgo.It("should convert a token context into an argo cd cluster", func() {
+		details := api.NewConfig()
+		details.CurrentContext = "eks1"
+		details.Contexts["eks1"] = &api.Context{Cluster: "eks1", AuthInfo: "eks1"}
+		details.Clusters["eks1"] = &api.Cluster{
+			Server:                   "https://192.168.1.2:4510",
+			CertificateAuthorityData: []byte("ca"),
+		}
+		details.AuthInfos["eks1"] = &api.AuthInfo{Token: "k8s-aws-v1.token"}
+
+		data, err := argoCDSecretData("eks1", details, "default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveKeyWithValue("name", []byte("eks1")))
+		Expect(data).To(HaveKeyWithValue("server", []byte("https://192.168.1.2:4510")))
+		Expect(data).To(HaveKeyWithValue("project", []byte("default")))
+
+		var config argoCDClusterConfig
+		Expect(json.Unmarshal(data["config"], &config)).To(Succeed())
+		Expect(config.BearerToken).To(Equal("k8s-aws-v1.token"))
+		Expect(config.TLSClientConfig.CAData).To(Equal([]byte("ca")))
+		Expect(config.TLSClientConfig.CertData).To(BeEmpty())
+	})
+
+	ginkgo.It("should pass exec plugins through to argo cd", func() {
+		details := api.NewConfig()
+		details.CurrentContext = "gke"
+		details.Contexts["gke"] = &api.Context{Cluster: "gke", AuthInfo: "gke"}
+		details.Clusters["gke"] = &api.Cluster{Server: "https://192.168.1.2:6443"}
+		details.AuthInfos["gke"] = &api.AuthInfo{Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "gke-gcloud-auth-plugin",
+			Env:        []api.ExecEnvVar{{Name: "CLOUDSDK_CORE_PROJECT", Value: "dev"}},
+		}}
+
+		data, err := argoCDSecretData("gke", details, "")
+		Expect(err).NotTo(HaveOccurred())
+
+		var config argoCDClusterConfig
+		Expect(json.Unmarshal(data["config"], &config)).To(Succeed())
+		Expect(config.ExecProviderConfig).NotTo(BeNil())
+		Expect(config.ExecProviderConfig.Command).To(Equal("gke-gcloud-auth-plugin"))
+		Expect(config.ExecProviderConfig.APIVersion).To(Equal("client.authentication.k8s.io/v1beta1"))
+		Expect(config.ExecProviderConfig.Env).To(HaveKeyWithValue("CLOUDSDK_CORE_PROJECT", "dev"))
+	})
+})
+
+var _ = ginkgo.Describe("earliest", func() {
+	ginkgo.It("should use the argo cd expiry when the kubeconfig secret does not expire", func() {
+		argoExpiry := time.Now().Add(time.Hour)
+
+		Expect(earliest(time.Time{}, argoExpiry)).To(Equal(argoExpiry))
+		Expect(earliest(argoExpiry, time.Time{})).To(Equal(argoExpiry))
+		Expect(earliest(time.Time{}, time.Time{}).IsZero()).To(BeTrue())
+	})
+
+	ginkgo.It("should return the earlier of two expiry times", func() {
+		soon, later := time.Now().Add(time.Minute), time.Now().Add(time.Hour)
+
+		Expect(earliest(later, soon)).To(Equal(soon))
+		Expect(earliest(soon, later)).To(Equal(soon))
+	})
+})
@@ -3,7 +3,6 @@ package aws
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -11,12 +10,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	credsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/mproffitt/kubeconfig-operator/internal/helpers"
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
@@ -28,16 +26,18 @@ const (
 	LOCALSTACK_SECRET_TOKEN = "test"
 	DEFAULT_REGION          = "us-east-1"
 
-	kindExecCredential     = "ExecCredential"
 	presignedURLExpiration = 15 * time.Minute
 	clusterIDHeader        = "x-k8s-aws-id"
 	v1Prefix               = "k8s-aws-v1."
 )
 
-func KubeConfig(context string, config *rest.Config) (cfg *api.Config, err error) {
+// KubeConfig builds a kubeconfig for an EKS cluster containing a presigned
+// bearer token. The time at which the token expires is returned alongside
+// the config so that it can be refreshed before it becomes invalid.
+func KubeConfig(context string, id Identity, config *rest.Config) (cfg *api.Config, expiry time.Time, err error) {
 	var token string
-	if token, err = getToken(context, config.Host); err != nil {
-		return nil, err
+	if token, expiry, err = getToken(id, config.Host); err != nil {
+		return nil, expiry, err
 	}
 
 	cfg = &api.Config{
@@ -46,6 +46,7 @@ func KubeConfig(context string, config *rest.Config) (cfg *api.Config, err error
 			context: {
 				Server:                   config.Host,
 				CertificateAuthorityData: config.CAData,
+				TLSServerName:            config.ServerName,
 			},
 		},
 		Contexts: map[string]*api.Context{
@@ -62,24 +63,35 @@ func KubeConfig(context string, config *rest.Config) (cfg *api.Config, err error
 		},
 	}
 
-	return cfg, nil
+	return cfg, expiry, nil
 }
 
-func getToken(arn, host string) (string, error) {
+func getToken(id Identity, host string) (string, time.Time, error) {
 	var (
 		err    error
 		client *sts.PresignClient
 	)
 
-	awsArn := NewArn(arn)
-
-	stsc, err := stsclient(awsArn.Region, host)
+	stsc, err := stsclient(id.Region, id.Profile, host)
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to create AWS STS client")
+		return "", time.Time{}, errors.Wrap(err, "Failed to create AWS STS client")
+	}
+
+	// Sign the token with the credentials of the role, as
+	// `aws eks get-token --role-arn` does.
+	if id.RoleARN != "" {
+		credentials := aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsc, id.RoleARN))
+		stsc = sts.New(stsc.Options(), func(o *sts.Options) {
+			o.Credentials = credentials
+		})
 	}
 
 	client = presignclient(stsc)
 
+	// The token is valid for presignedURLExpiration from the time it is
+	// signed. Report the expiry a minute early to allow for clock skew.
+	expiry := time.Now().Add(presignedURLExpiration - 1*time.Minute)
+
 	getCallerIdentity, err := client.PresignGetCallerIdentity(
 		context.TODO(),
 		&sts.GetCallerIdentityInput{},
@@ -88,7 +100,7 @@ func getToken(arn, host string) (string, error) {
 				// Add clusterId Header
 				stsOptions.APIOptions = append(
 					stsOptions.APIOptions,
-					smithyhttp.SetHeaderValue(clusterIDHeader, awsArn.ResourceName),
+					smithyhttp.SetHeaderValue(clusterIDHeader, id.ClusterName),
 				)
 				// Add back useless X-Amz-Expires query param
 				exp := fmt.Sprintf("%d", int(presignedURLExpiration.Minutes()))
@@ -96,14 +108,14 @@ func getToken(arn, host string) (string, error) {
 			})
 		})
 	if err != nil {
-		return "", errors.Wrap(err, "Failed to presign GetCallerIdentity")
+		return "", time.Time{}, errors.Wrap(err, "Failed to presign GetCallerIdentity")
 	}
 
 	token := base64.RawURLEncoding.EncodeToString([]byte(getCallerIdentity.URL))
-	return formatJSON(fmt.Sprintf("%s%s", v1Prefix, token)), nil
+	return fmt.Sprintf("%s%s", v1Prefix, token), expiry, nil
 }
 
-func stsclient(region, host string) (*sts.Client, error) {
+func stsclient(region, profile, host string) (*sts.Client, error) {
 	var (
 		accesskey                      = os.Getenv("AWS_ACCESS_KEY_ID")
 		endpoint                       = os.Getenv("AWS_ENDPOINT")
@@ -115,10 +127,6 @@ func stsclient(region, host string) (*sts.Client, error) {
 		err            error
 	)
 
-	if region == "" {
-		region = DEFAULT_REGION
-	}
-
 	if localstackHost == "" {
 		localstackHost = LOCALSTACK_ENDPOINT
 	}
@@ -147,10 +155,18 @@ func stsclient(region, host string) (*sts.Client, error) {
 		}
 	}
 
-	opts = append(opts, config.WithRegion(region))
-	opts = append(opts, config.WithCredentialsProvider(
-		credsv2.NewStaticCredentialsProvider(accesskey, secretkey, session),
-	))
+	// An explicit region would take precedence over the one in the
+	// profile, so it is only set when one was given.
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if accesskey == "" && profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	} else {
+		opts = append(opts, config.WithCredentialsProvider(
+			credsv2.NewStaticCredentialsProvider(accesskey, secretkey, session),
+		))
+	}
 
 	var cfg aws.Config
 	if cfg, err = config.LoadDefaultConfig(
@@ -166,6 +182,10 @@ func stsclient(region, host string) (*sts.Client, error) {
 		return nil, errors.Wrap(err, "Failed to load AWS config")
 	}
 
+	if cfg.Region == "" {
+		cfg.Region = DEFAULT_REGION
+	}
+
 	client := sts.NewFromConfig(cfg, func(o *sts.Options) {
 		o.EndpointResolverV2 = &resolverV2{
 			Endpoint: endpoint,
@@ -179,23 +199,3 @@ func presignclient(client *sts.Client) *sts.PresignClient {
 
 	return presign
 }
-
-func formatJSON(token string) string {
-	expTime := time.Now().Local().Add(presignedURLExpiration - 1*time.Minute)
-	expirationTimestamp := metav1.NewTime(expTime)
-
-	apiVersion := clientauthv1beta1.SchemeGroupVersion.String()
-	execObj := &clientauthv1beta1.ExecCredential{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: apiVersion,
-			Kind:       kindExecCredential,
-		},
-		Status: &clientauthv1beta1.ExecCredentialStatus{
-			ExpirationTimestamp: &expirationTimestamp,
-			Token:               token,
-		},
-	}
-
-	jsonData, _ := json.Marshal(execObj)
-	return string(jsonData)
-}
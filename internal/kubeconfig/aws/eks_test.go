@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package aws
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("stsclient", func() {
+	const host = "https://ABCDEF.gr7.eu-west-2.eks.amazonaws.com:443"
+
+	ginkgo.BeforeEach(func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		config := filepath.Join(dir, "config")
+		Expect(os.WriteFile(config, []byte("[profile dev]\nregion = eu-west-2\n"), 0o600)).To(Succeed())
+
+		ginkgo.GinkgoT().Setenv("AWS_CONFIG_FILE", config)
+		ginkgo.GinkgoT().Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+		for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE"} {
+			ginkgo.GinkgoT().Setenv(name, "")
+		}
+	})
+
+	ginkgo.It("should use the region of the profile", func() {
+		client, err := stsclient("", "dev", host)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Options().Region).To(Equal("eu-west-2"))
+	})
+
+	ginkgo.It("should prefer the region given in the exec plugin", func() {
+		client, err := stsclient("eu-west-1", "dev", host)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Options().Region).To(Equal("eu-west-1"))
+	})
+
+	ginkgo.It("should fall back to the default region", func() {
+		client, err := stsclient("", "", host)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Options().Region).To(Equal(DEFAULT_REGION))
+	})
+})
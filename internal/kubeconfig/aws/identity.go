@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package aws
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Identity is the EKS cluster a token is generated for and the AWS
+// credentials used to sign it.
+type Identity struct {
+	// ClusterName is the name or ID of the EKS cluster, sent in the
+	// x-k8s-aws-id header of the token.
+	ClusterName string
+
+	// Region is the region of the STS endpoint used to sign the token.
+	Region string
+
+	// RoleARN is an IAM role assumed before signing the token.
+	RoleARN string
+
+	// Profile is the shared config profile to load credentials from.
+	Profile string
+}
+
+// Flags read from the exec args, by command.
+var (
+	clusterNameFlags = map[string][]string{
+		"aws":                   {"cluster-name", "cluster-id"},
+		"aws-iam-authenticator": {"i", "cluster-id"},
+	}
+	roleFlags = map[string][]string{
+		"aws":                   {"role-arn"},
+		"aws-iam-authenticator": {"r", "role"},
+	}
+)
+
+// ResolveIdentity determines the EKS cluster and credentials for a user
+// from its exec plugin, as written by `aws eks update-kubeconfig` or for
+// aws-iam-authenticator. The cluster name and region are read from the
+// cluster ARN only when the exec plugin does not give them, so contexts
+// and clusters may be given any name. The region is left empty if none is
+// given, so that it can be taken from the profile.
+func ResolveIdentity(cluster string, exec *api.ExecConfig) (Identity, error) {
+	id := identityFromExec(exec)
+
+	if id.ClusterName == "" || id.Region == "" {
+		arn, err := clusterArn(cluster)
+		switch {
+		case err == nil:
+			if id.ClusterName == "" {
+				id.ClusterName = arn.ResourceName
+			}
+			if id.Region == "" {
+				id.Region = arn.Region
+			}
+		case id.ClusterName == "":
+			return id, errors.Wrap(err,
+				"cannot determine the EKS cluster, expected --cluster-name in the exec args or a cluster ARN")
+		}
+	}
+
+	if id.RoleARN != "" {
+		if err := roleArn(id.RoleARN); err != nil {
+			return id, errors.Wrap(err, "invalid role")
+		}
+	}
+
+	return id, nil
+}
+
+// identityFromExec reads the identity given in the args and env of an
+// exec plugin. Flags take precedence over the environment.
+func identityFromExec(exec *api.ExecConfig) Identity {
+	id := Identity{}
+	if exec == nil {
+		return id
+	}
+
+	env := make(map[string]string, len(exec.Env))
+	for _, e := range exec.Env {
+		env[e.Name] = e.Value
+	}
+
+	command := filepath.Base(exec.Command)
+	flags := provider.ParseFlags(exec.Args)
+
+	id.ClusterName = first(flags, clusterNameFlags[command]...)
+	id.RoleARN = first(flags, roleFlags[command]...)
+	id.Region = first(flags, "region")
+	if id.Region == "" {
+		id.Region = first(env, "AWS_REGION", "AWS_DEFAULT_REGION")
+	}
+	id.Profile = first(flags, "profile")
+	if id.Profile == "" {
+		id.Profile = env["AWS_PROFILE"]
+	}
+
+	return id
+}
+
+// first returns the first non empty value in values for the given keys.
+func first(values map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v := values[key]; v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
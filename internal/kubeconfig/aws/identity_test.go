@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+var _ = ginkgo.Describe("EKS identity", func() {
+	getToken := func(args ...string) *api.ExecConfig {
+		return &api.ExecConfig{Command: "aws", Args: append([]string{"eks", "get-token"}, args...)}
+	}
+
+	ginkgo.It("should read the cluster and region from aws eks get-token", func() {
+		id, err := ResolveIdentity("testeks", getToken(
+			"--cluster-name", "eks1", "--region=eu-west-1", "--output", "json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(Identity{ClusterName: "eks1", Region: "eu-west-1"}))
+	})
+
+	ginkgo.It("should read the role and profile", func() {
+		exec := getToken("--cluster-name", "eks1", "--role-arn", "arn:aws:iam::000000000000:role/admin")
+		exec.Env = []api.ExecEnvVar{{Name: "AWS_PROFILE", Value: "dev"}, {Name: "AWS_REGION", Value: "eu-west-2"}}
+
+		id, err := ResolveIdentity("testeks", exec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(Identity{
+			ClusterName: "eks1",
+			Region:      "eu-west-2",
+			RoleARN:     "arn:aws:iam::000000000000:role/admin",
+			Profile:     "dev",
+		}))
+	})
+
+	ginkgo.It("should read the cluster from aws-iam-authenticator", func() {
+		id, err := ResolveIdentity("testeks", &api.ExecConfig{
+			Command: "/usr/local/bin/aws-iam-authenticator",
+			Args:    []string{"token", "-i", "eks1", "-r", "arn:aws:iam::000000000000:role/admin"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id.ClusterName).To(Equal("eks1"))
+		Expect(id.RoleARN).To(Equal("arn:aws:iam::000000000000:role/admin"))
+		// The region is left to the profile, or DEFAULT_REGION
+		Expect(id.Region).To(BeEmpty())
+	})
+
+	ginkgo.It("should fall back to the cluster ARN", func() {
+		id, err := ResolveIdentity("arn:aws:eks:eu-west-1:000000000000:cluster/eks1", getToken())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(Identity{ClusterName: "eks1", Region: "eu-west-1"}))
+
+		// The args take precedence over the ARN
+		id, err = ResolveIdentity("arn:aws:eks:eu-west-1:000000000000:cluster/eks1",
+			getToken("--cluster-name", "eks2"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal(Identity{ClusterName: "eks2", Region: "eu-west-1"}))
+	})
+
+	ginkgo.It("should fail when the cluster cannot be determined", func() {
+		_, err := ResolveIdentity("testeks", getToken())
+		Expect(err).To(MatchError(ContainSubstring(`"testeks" is not an ARN`)))
+
+		_, err = ResolveIdentity("arn:aws:iam::000000000000:role/admin", getToken())
+		Expect(err).To(MatchError(ContainSubstring("is not an EKS cluster")))
+	})
+
+	ginkgo.It("should reject an invalid role", func() {
+		_, err := ResolveIdentity("testeks", getToken("--cluster-name", "eks1", "--role-arn", "admin"))
+		Expect(err).To(MatchError(ContainSubstring("invalid role")))
+
+		_, err = ResolveIdentity("testeks", getToken(
+			"--cluster-name", "eks1", "--role-arn", "arn:aws:eks:eu-west-1:000000000000:cluster/eks1"))
+		Expect(err).To(MatchError(ContainSubstring("is not an IAM role")))
+	})
+
+	ginkgo.It("should reject malformed ARNs", func() {
+		for _, arn := range []string{"", "arn:aws", "arn::eks:eu-west-1:000000000000:", "foo:aws:eks:::cluster/x"} {
+			_, err := NewArn(arn)
+			Expect(err).To(HaveOccurred(), arn)
+		}
+	})
+
+	ginkgo.It("should sign a token for an aliased localstack context", func() {
+		details, expiry, err := Provider{}.KubeConfig(context.Background(), provider.Request{
+			Context:  "testeks",
+			Cluster:  "testeks",
+			User:     "testeks",
+			AuthInfo: &api.AuthInfo{Exec: getToken("--cluster-name", "eks1", "--region", "eu-west-1")},
+			Config: &rest.Config{
+				Host:     "https://localhost.localstack.cloud:4510",
+				Username: "testeks",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry.IsZero()).To(BeFalse())
+
+		token := details.AuthInfos["testeks"].Token
+		Expect(token).To(HavePrefix("k8s-aws-v1."))
+		url, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, "k8s-aws-v1."))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(url)).To(ContainSubstring("eu-west-1%2Fsts"))
+		Expect(string(url)).To(ContainSubstring("x-k8s-aws-id"))
+	})
+})
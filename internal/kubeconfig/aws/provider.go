@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package aws
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Name is the name of the EKS provider.
+const Name = "aws"
+
+// Provider exports EKS contexts with a presigned bearer token.
+type Provider struct{}
+
+var _ provider.Provider = Provider{}
+
+func (Provider) Name() string {
+	return Name
+}
+
+// Detect returns true if the user authenticates with `aws eks get-token`
+// or aws-iam-authenticator.
+func (Provider) Detect(authInfo *api.AuthInfo) bool {
+	if authInfo.Exec == nil {
+		return false
+	}
+
+	switch filepath.Base(authInfo.Exec.Command) {
+	case "aws", "aws-iam-authenticator":
+		return true
+	}
+
+	return false
+}
+
+// KubeConfig generates a token for the EKS cluster given in the exec args
+// of the user, falling back to the cluster ARN.
+func (Provider) KubeConfig(_ context.Context, req provider.Request) (*api.Config, time.Time, error) {
+	id, err := ResolveIdentity(req.Cluster, req.AuthInfo.Exec)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return KubeConfig(req.Cluster, id, req.Config)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	smithyendpoints "github.com/aws/smithy-go/endpoints"
+	"github.com/pkg/errors"
 )
 
 type awsArn struct {
@@ -18,15 +19,20 @@ type awsArn struct {
 	ResourceName string
 }
 
-func NewArn(arn string) *awsArn {
-	var (
-		parts                  = strings.Split(arn, ":")
-		resourceParts          []string
-		resource, resourceName string
-	)
+// NewArn parses an ARN of the form
+// arn:partition:service:region:account-id:resource-type/resource-id.
+func NewArn(arn string) (*awsArn, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return nil, errors.Errorf("%q is not an ARN", arn)
+	}
+
+	if parts[1] == "" || parts[2] == "" || parts[5] == "" {
+		return nil, errors.Errorf("ARN %q is missing its partition, service or resource", arn)
+	}
 
-	resourceParts = strings.Split(parts[5], "/")
-	if len(resourceParts) > 1 {
+	var resource, resourceName string
+	if resourceParts := strings.SplitN(parts[5], "/", 2); len(resourceParts) > 1 {
 		resource = resourceParts[0]
 		resourceName = resourceParts[1]
 	}
@@ -38,7 +44,35 @@ func NewArn(arn string) *awsArn {
 		AccountID:    parts[4],
 		Resource:     resource,
 		ResourceName: resourceName,
+	}, nil
+}
+
+// clusterArn parses the ARN of an EKS cluster.
+func clusterArn(arn string) (*awsArn, error) {
+	a, err := NewArn(arn)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Service != "eks" || a.Resource != "cluster" || a.ResourceName == "" {
+		return nil, errors.Errorf("ARN %q is not an EKS cluster", arn)
 	}
+
+	return a, nil
+}
+
+// roleArn checks that arn is the ARN of an IAM role.
+func roleArn(arn string) error {
+	a, err := NewArn(arn)
+	if err != nil {
+		return err
+	}
+
+	if a.Service != "iam" || a.Resource != "role" || a.ResourceName == "" {
+		return errors.Errorf("ARN %q is not an IAM role", arn)
+	}
+
+	return nil
 }
 
 type resolverV2 struct {
@@ -0,0 +1,177 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+	"github.com/mproffitt/kubeconfig-operator/internal/helpers"
+)
+
+const (
+	// ClusterAPISecretType is the type of Cluster API kubeconfig secrets.
+	ClusterAPISecretType = "cluster.x-k8s.io/secret"
+
+	// LabelClusterAPIClusterName is the label Cluster API uses to find the
+	// secrets belonging to a cluster.
+	LabelClusterAPIClusterName = "cluster.x-k8s.io/cluster-name"
+)
+
+// clusterAPIClusterGVK is the Cluster API Cluster kind. It is handled as
+// unstructured so that Cluster API is not a dependency of the operator.
+var clusterAPIClusterGVK = schema.GroupVersionKind{
+	Group:   "cluster.x-k8s.io",
+	Version: "v1beta1",
+	Kind:    "Cluster",
+}
+
+// clusterAPIName is the name of the Cluster API cluster for a target. Cluster
+// API expects the kubeconfig secret to be called `<cluster>-kubeconfig`.
+func clusterAPIName(t target) string {
+	return strings.TrimSuffix(t.secretName, "-kubeconfig")
+}
+
+// createClusterAPIClusterForCluster creates or updates a paused placeholder
+// Cluster API Cluster pointing at the context's API server.
+func (m *Manager) createClusterAPIClusterForCluster(t target, details *api.Config) error {
+	if t.format.ClusterAPI == nil || !t.format.ClusterAPI.CreateCluster {
+		return nil
+	}
+
+	cluster, ok := details.Clusters[t.name]
+	if !ok {
+		return errors.New("cluster not found")
+	}
+
+	_, host, port, err := helpers.AddressToSchemeHostPort(cluster.Server)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse server address")
+	}
+
+	portNumber, err := strconv.ParseInt(port, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "cannot parse server port")
+	}
+
+	endpoint := map[string]interface{}{
+		"host": host,
+		"port": portNumber,
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(clusterAPIClusterGVK)
+	err = m.client.Get(m.context, client.ObjectKey{Namespace: t.namespace, Name: clusterAPIName(t)}, obj)
+	if apierrors.IsNotFound(err) {
+		obj = &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(clusterAPIClusterGVK)
+		obj.SetName(clusterAPIName(t))
+		obj.SetNamespace(t.namespace)
+		obj.SetLabels(t.labels)
+		obj.SetAnnotations(t.annotations)
+		if err = unstructured.SetNestedField(obj.Object, true, "spec", "paused"); err != nil {
+			return err
+		}
+		if err = unstructured.SetNestedMap(obj.Object, endpoint, "spec", "controlPlaneEndpoint"); err != nil {
+			return err
+		}
+
+		if err = m.client.Create(m.context, obj); err != nil {
+			return errors.Wrap(err, "failed to create cluster api cluster")
+		}
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to get cluster api cluster")
+	}
+
+	// Never modify a Cluster that the operator did not create
+	labels := obj.GetLabels()
+	if labels[LabelClusterName] != m.cluster.Name || labels[LabelClusterNamespace] != m.cluster.Namespace {
+		m.log.Info("cluster api cluster exists and is not owned by this cluster, skipping update",
+			"namespace", t.namespace, "name", obj.GetName())
+		return nil
+	}
+
+	current, _, _ := unstructured.NestedMap(obj.Object, "spec", "controlPlaneEndpoint")
+	if current["host"] == endpoint["host"] && current["port"] == endpoint["port"] {
+		return nil
+	}
+
+	if err = unstructured.SetNestedMap(obj.Object, endpoint, "spec", "controlPlaneEndpoint"); err != nil {
+		return err
+	}
+
+	if err = m.client.Update(m.context, obj); err != nil {
+		return errors.Wrap(err, "failed to update cluster api cluster")
+	}
+
+	return nil
+}
+
+// createsClusterAPIClusters returns true if placeholder Cluster API
+// Clusters are created for any context of the Cluster.
+func (m *Manager) createsClusterAPIClusters() bool {
+	creates := func(format *kccnv1alpha1.SecretFormat) bool {
+		return format != nil && format.ClusterAPI != nil && format.ClusterAPI.CreateCluster
+	}
+
+	if creates(m.cluster.Spec.SecretFormat) {
+		return true
+	}
+	for _, override := range m.cluster.Spec.Overrides {
+		if creates(override.SecretFormat) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ownedClusterAPIClusters lists the placeholder Cluster API Clusters created
+// for the Cluster. Nothing is listed unless Clusters are created or were
+// created on an earlier reconcile, so that the operator does not need
+// access to Cluster API otherwise. If the Cluster API CRDs are not
+// installed, nothing is returned.
+func (m *Manager) ownedClusterAPIClusters() ([]unstructured.Unstructured, error) {
+	if !m.createsClusterAPIClusters() && !m.cluster.Status.ClusterAPIClusters {
+		return nil, nil
+	}
+
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(clusterAPIClusterGVK.GroupVersion().WithKind("ClusterList"))
+
+	err := m.client.List(m.context, list, client.MatchingLabels(m.ownerLabels()))
+	if meta.IsNoMatchError(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list cluster api clusters")
+	}
+
+	return list.Items, nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("Cluster API secrets", func() {
+	var (
+		m       *Manager
+		details *api.Config
+	)
+
+	ginkgo.BeforeEach(func() {
+		m = newTestManager()
+
+		details = api.NewConfig()
+		details.CurrentContext = "kind-tenant1"
+		details.Contexts["kind-tenant1"] = &api.Context{Cluster: "kind-tenant1", AuthInfo: "kind-tenant1"}
+		details.Clusters["kind-tenant1"] = &api.Cluster{Server: "https://192.168.1.2:6443"}
+		details.AuthInfos["kind-tenant1"] = &api.AuthInfo{ClientCertificateData: []byte("cert")}
+	})
+
+	getSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Namespace: "cluster-kind-tenant1", Name: "kind-tenant1-kubeconfig"}
+		Expect(m.client.Get(m.context, key, secret)).To(Succeed())
+		return secret
+	}
+
+	ginkgo.It("should write a cluster api secret", func() {
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{
+			Keys:       []string{"kubeconfig"},
+			ClusterAPI: &kccnv1alpha1.ClusterAPIFormat{},
+		}
+
+		_, err := m.createSecretForCluster(m.targetFor(Context{name: "kind-tenant1"}), details, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := getSecret()
+		Expect(secret.Type).To(Equal(corev1.SecretType(ClusterAPISecretType)))
+		Expect(secret.Labels).To(HaveKeyWithValue(LabelClusterAPIClusterName, "kind-tenant1"))
+		Expect(secret.Data).To(HaveKey("value"))
+		Expect(secret.Data).To(HaveKey("kubeconfig"))
+	})
+
+	ginkgo.It("should recreate the secret when the type changes", func() {
+		t := m.targetFor(Context{name: "kind-tenant1"})
+		_, err := m.createSecretForCluster(t, details, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getSecret().Type).To(Equal(corev1.SecretTypeOpaque))
+
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{
+			ClusterAPI: &kccnv1alpha1.ClusterAPIFormat{},
+		}
+		_, err = m.createSecretForCluster(m.targetFor(Context{name: "kind-tenant1"}), details, time.Time{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getSecret().Type).To(Equal(corev1.SecretType(ClusterAPISecretType)))
+	})
+
+	ginkgo.It("should require the -kubeconfig suffix on secret names used with cluster api", func() {
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{ClusterAPI: &kccnv1alpha1.ClusterAPIFormat{}}
+		Expect(m.targetFor(Context{name: "kind-tenant1"}).validate()).To(Succeed())
+
+		for _, name := range []string{"tenant1", "-kubeconfig"} {
+			m.cluster.Spec.Overrides = []kccnv1alpha1.ContextOverride{{
+				Context:    "kind-tenant1",
+				SecretName: name,
+			}}
+			Expect(m.targetFor(Context{name: "kind-tenant1"}).validate()).
+				To(MatchError(ContainSubstring("must be <cluster>-kubeconfig")))
+		}
+
+		m.cluster.Spec.SecretFormat = nil
+		Expect(m.targetFor(Context{name: "kind-tenant1"}).validate()).To(Succeed())
+	})
+
+	ginkgo.It("should only list cluster api clusters when they are or were created", func() {
+		lists := 0
+		m.client = fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if _, ok := list.(*unstructured.UnstructuredList); ok {
+					lists++
+					return nil
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).Build()
+
+		_, err := m.ownedClusterAPIClusters()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lists).To(BeZero())
+
+		status := &Status{}
+		_, err = m.prune(status, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lists).To(BeZero())
+		Expect(status.ClusterAPIClusters).To(BeFalse())
+
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{
+			ClusterAPI: &kccnv1alpha1.ClusterAPIFormat{CreateCluster: true},
+		}
+		_, err = m.prune(status, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lists).To(Equal(1))
+		Expect(status.ClusterAPIClusters).To(BeTrue())
+
+		// Clusters created earlier are still pruned once creation is
+		// turned off, until none are left.
+		m.cluster.Spec.SecretFormat = nil
+		m.cluster.Status.ClusterAPIClusters = true
+		_, err = m.prune(status, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lists).To(Equal(2))
+		Expect(status.ClusterAPIClusters).To(BeFalse())
+	})
+})
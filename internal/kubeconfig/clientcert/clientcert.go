@@ -12,6 +12,7 @@ func KubeConfig(context string, config *rest.Config) (*api.Config, error) {
 			context: {
 				Server:                   config.Host,
 				CertificateAuthorityData: config.CAData,
+				TLSServerName:            config.ServerName,
 			},
 		},
 		Contexts: map[string]*api.Context{
@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package clientcert
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Name is the name of the client certificate provider.
+const Name = "client-cert"
+
+// Provider exports contexts authenticated with a client certificate.
+type Provider struct{}
+
+var _ provider.Provider = Provider{}
+
+func (Provider) Name() string {
+	return Name
+}
+
+// Detect returns true if the user has a client certificate, either inline
+// or referenced by file.
+func (Provider) Detect(authInfo *api.AuthInfo) bool {
+	return authInfo.Exec == nil && authInfo.AuthProvider == nil &&
+		(len(authInfo.ClientCertificateData) > 0 || authInfo.ClientCertificate != "")
+}
+
+func (Provider) KubeConfig(_ context.Context, req provider.Request) (*api.Config, time.Time, error) {
+	cfg, err := KubeConfig(req.Context, req.Config)
+	return cfg, time.Time{}, err
+}
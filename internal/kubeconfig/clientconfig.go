@@ -1,23 +1,20 @@
 package kubeconfig
 
 import (
+	"strings"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/helpers"
 	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
-	clientconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
 func ClientConfig(context, remapAddress string, options GetContextsOptions) (*kconfig, error) {
-	c := &kconfig{}
-	var err error
-
-	if c.Config, err = clientconfig.GetConfigWithContext(context); err != nil {
-		return nil, errors.Wrap(err, "cannot get cluster config with context "+context)
+	c := &kconfig{
+		Config: &rest.Config{},
 	}
 
-	config, err := options.configAccess.GetStartingConfig()
-	if err != nil {
-		return nil, errors.Wrap(err, "cannot get starting config")
-	}
+	config := options.config
 
 	var (
 		user     string
@@ -39,17 +36,7 @@ func ClientConfig(context, remapAddress string, options GetContextsOptions) (*kc
 		return nil, errors.New("auth info not found")
 	}
 
-	var cmd string
-	if authInfo.Exec != nil {
-		cmd = authInfo.Exec.Command
-	}
-
-	switch cmd {
-	case "aws-iam-authenticator", "aws":
-		c.provider = ProviderKindAWS
-	default:
-		c.provider = ProviderKindClientCert
-	}
+	c.authInfo = authInfo
 
 	host, oldhost, port, err := remap(cluster.Server, remapAddress)
 	if err != nil {
@@ -61,11 +48,39 @@ func ClientConfig(context, remapAddress string, options GetContextsOptions) (*kc
 	c.port = port
 
 	c.Host = host
-	c.CAData = cluster.CertificateAuthorityData
-	c.CAFile = cluster.CertificateAuthority
+	c.TLSClientConfig.ServerName = cluster.TLSServerName
+	if options.tlsServerName && c.TLSClientConfig.ServerName == "" && host != cluster.Server {
+		if _, c.TLSClientConfig.ServerName, _, err = helpers.AddressToSchemeHostPort(cluster.Server); err != nil {
+			return nil, errors.Wrap(err, "cannot parse server address")
+		}
+	}
+
+	if c.CAData, err = options.inline(
+		cluster.CertificateAuthorityData, cluster.CertificateAuthority, cluster.LocationOfOrigin,
+	); err != nil {
+		return nil, errors.Wrap(err, "cannot read certificate-authority")
+	}
+
+	if c.CertData, err = options.inline(
+		authInfo.ClientCertificateData, authInfo.ClientCertificate, authInfo.LocationOfOrigin,
+	); err != nil {
+		return nil, errors.Wrap(err, "cannot read client-certificate")
+	}
+	if c.KeyData, err = options.inline(
+		authInfo.ClientKeyData, authInfo.ClientKey, authInfo.LocationOfOrigin,
+	); err != nil {
+		return nil, errors.Wrap(err, "cannot read client-key")
+	}
+	c.BearerToken = authInfo.Token
+	if c.BearerToken == "" && authInfo.TokenFile != "" {
+		var token []byte
+		if token, err = options.inline(nil, authInfo.TokenFile, authInfo.LocationOfOrigin); err != nil {
+			return nil, errors.Wrap(err, "cannot read tokenFile")
+		}
+		c.BearerToken = strings.TrimSpace(string(token))
+	}
 
-	c.CertData = authInfo.ClientCertificateData
-	c.KeyData = authInfo.ClientKeyData
+	c.Password = authInfo.Password
 	c.Username = authInfo.Username
 	if c.Username == "" {
 		c.Username = user
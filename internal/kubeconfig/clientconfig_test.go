@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/clientcert"
+)
+
+var _ = ginkgo.Describe("ClientConfig", func() {
+	var options GetContextsOptions
+
+	ginkgo.BeforeEach(func() {
+		config := api.NewConfig()
+		config.Contexts["kind-tenant1"] = &api.Context{Cluster: "kind-tenant1", AuthInfo: "kind-tenant1"}
+		config.Clusters["kind-tenant1"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["kind-tenant1"] = &api.AuthInfo{ClientCertificateData: []byte("cert")}
+		options = GetContextsOptions{config: config}
+	})
+
+	ginkgo.It("should not set a tls server name by default", func() {
+		c, err := ClientConfig("kind-tenant1", "192.168.1.2", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Host).To(Equal("https://192.168.1.2:6443"))
+		Expect(c.TLSClientConfig.ServerName).To(BeEmpty())
+	})
+
+	ginkgo.It("should set the original host as tls server name when remapped", func() {
+		options.tlsServerName = true
+		c, err := ClientConfig("kind-tenant1", "192.168.1.2", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.TLSClientConfig.ServerName).To(Equal("127.0.0.1"))
+
+		details, err := clientcert.KubeConfig("kind-tenant1", c.Config)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(details.Clusters["kind-tenant1"].TLSServerName).To(Equal("127.0.0.1"))
+	})
+
+	ginkgo.It("should keep an existing tls server name", func() {
+		options.tlsServerName = true
+		options.config.Clusters["kind-tenant1"].TLSServerName = "kubernetes"
+		c, err := ClientConfig("kind-tenant1", "192.168.1.2", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.TLSClientConfig.ServerName).To(Equal("kubernetes"))
+	})
+
+	ginkgo.It("should not set a tls server name when the host is not remapped", func() {
+		options.tlsServerName = true
+		options.config.Clusters["kind-tenant1"].Server = "https://10.0.0.1:6443"
+		c, err := ClientConfig("kind-tenant1", "192.168.1.2", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.TLSClientConfig.ServerName).To(BeEmpty())
+	})
+})
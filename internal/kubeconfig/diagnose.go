@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// tlsDialTimeout bounds the handshake used to diagnose an unreachable
+// cluster.
+const tlsDialTimeout = 5 * time.Second
+
+// probeError is returned by the availability check when the cause of the
+// failure is known.
+type probeError struct {
+	reason string
+	err    error
+}
+
+func (e *probeError) Error() string {
+	return e.reason + ": " + e.err.Error()
+}
+
+func (e *probeError) Unwrap() error {
+	return e.err
+}
+
+// diagnoseTLS performs a TLS handshake with the API server and inspects
+// the presented certificate. It returns a probeError describing why the
+// certificate would be rejected, or nil if the certificate is acceptable
+// or the server could not be reached at all.
+func diagnoseTLS(config *rest.Config, remapIp string) error {
+	u, err := url.Parse(config.Host)
+	if err != nil || u.Scheme != "https" || config.Insecure {
+		return nil
+	}
+
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: tlsDialTimeout}, "tcp", address, &tls.Config{
+		ServerName: config.ServerName,
+		// The certificate is verified below so that the reason it would
+		// be rejected can be reported.
+		InsecureSkipVerify: true, //nolint:gosec
+	})
+	if err != nil {
+		return nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil
+	}
+
+	return checkCertificate(certs, config.CAData, verifyName(config, u.Hostname(), remapIp))
+}
+
+// name is the name a certificate is verified against, along with where
+// that name came from for reporting.
+type name struct {
+	value string
+	kind  string
+}
+
+// verifyName returns the name a client using config verifies the server
+// certificate against.
+func verifyName(config *rest.Config, host, remapIp string) name {
+	switch {
+	case config.ServerName != "":
+		return name{value: config.ServerName, kind: "tls-server-name"}
+	case host == remapIp:
+		return name{value: host, kind: "remap IP"}
+	default:
+		return name{value: host, kind: "host"}
+	}
+}
+
+// checkCertificate verifies the presented chain against the CA bundle and
+// the expected name.
+func checkCertificate(certs []*x509.Certificate, caData []byte, expected name) error {
+	leaf := certs[0]
+	now := time.Now()
+
+	if now.After(leaf.NotAfter) {
+		return &probeError{
+			reason: kccnv1alpha1.ReasonCertificateExpired,
+			err:    errors.Errorf("certificate expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339)),
+		}
+	}
+	if now.Before(leaf.NotBefore) {
+		return &probeError{
+			reason: kccnv1alpha1.ReasonCertificateExpired,
+			err:    errors.Errorf("certificate not valid until %s", leaf.NotBefore.UTC().Format(time.RFC3339)),
+		}
+	}
+
+	if err := leaf.VerifyHostname(expected.value); err != nil {
+		return &probeError{
+			reason: kccnv1alpha1.ReasonCertificateSANMismatch,
+			err:    errors.Errorf("%s %s not in SANs %v", expected.kind, expected.value, subjectAltNames(leaf)),
+		}
+	}
+
+	var roots *x509.CertPool
+	if len(caData) > 0 {
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caData) {
+			return &probeError{
+				reason: kccnv1alpha1.ReasonCertificateUnknownAuthority,
+				err:    errors.New("certificate-authority-data contains no valid certificates"),
+			}
+		}
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+		return &probeError{
+			reason: kccnv1alpha1.ReasonCertificateUnknownAuthority,
+			err:    errors.Errorf("certificate issued by %q is not trusted by the kubeconfig CA", leaf.Issuer.String()),
+		}
+	}
+
+	return nil
+}
+
+// subjectAltNames lists the DNS names and IP addresses in a certificate.
+func subjectAltNames(cert *x509.Certificate) []string {
+	sans := make([]string, 0, len(cert.IPAddresses)+len(cert.DNSNames))
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	sans = append(sans, cert.DNSNames...)
+
+	return sans
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("TLS diagnostics", func() {
+	var (
+		server *httptest.Server
+		caData []byte
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+		caData = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	reasonOf := func(err error) string {
+		var probeErr *probeError
+		Expect(errors.As(err, &probeErr)).To(BeTrue())
+		return probeErr.reason
+	}
+
+	ginkgo.It("should accept a valid certificate", func() {
+		config := &rest.Config{Host: server.URL}
+		config.CAData = caData
+		Expect(diagnoseTLS(config, "")).To(Succeed())
+	})
+
+	ginkgo.It("should report a SAN mismatch for the tls server name", func() {
+		config := &rest.Config{Host: server.URL}
+		config.CAData = caData
+		config.ServerName = "kubernetes.internal"
+
+		err := diagnoseTLS(config, "")
+		Expect(reasonOf(err)).To(Equal(kccnv1alpha1.ReasonCertificateSANMismatch))
+		Expect(err.Error()).To(ContainSubstring("tls-server-name kubernetes.internal not in SANs [127.0.0.1 ::1 example.com *.example.com]"))
+	})
+
+	ginkgo.It("should report a certificate from an unknown authority", func() {
+		config := &rest.Config{Host: server.URL}
+		Expect(reasonOf(diagnoseTLS(config, ""))).To(Equal(kccnv1alpha1.ReasonCertificateUnknownAuthority))
+	})
+
+	ginkgo.It("should ignore servers that cannot be reached", func() {
+		config := &rest.Config{Host: "https://127.0.0.1:1"}
+		Expect(diagnoseTLS(config, "")).To(Succeed())
+	})
+
+	ginkgo.It("should name the remap IP when it is missing from the SANs", func() {
+		expected := verifyName(&rest.Config{}, "192.168.1.2", "192.168.1.2")
+		err := checkCertificate([]*x509.Certificate{server.Certificate()}, caData, expected)
+		Expect(reasonOf(err)).To(Equal(kccnv1alpha1.ReasonCertificateSANMismatch))
+		Expect(err.Error()).To(HaveSuffix("remap IP 192.168.1.2 not in SANs [127.0.0.1 ::1 example.com *.example.com]"))
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Reasons used on events emitted for a Cluster. Changes to the readiness of
+// a context use the reason from its status entry.
+const (
+	EventReasonContextDiscovered     = "ContextDiscovered"
+	EventReasonNamespaceCreated      = "NamespaceCreated"
+	EventReasonSecretCreated         = "SecretCreated"
+	EventReasonSecretUpdated         = "SecretUpdated"
+	EventReasonFirewallRulesRequired = "FirewallRulesRequired"
+	EventReasonPruned                = "Pruned"
+)
+
+// event records a Normal event on the Cluster.
+func (m *Manager) event(reason, messageFmt string, args ...any) {
+	if m.recorder != nil {
+		m.recorder.Eventf(m.cluster, corev1.EventTypeNormal, reason, messageFmt, args...)
+	}
+}
+
+// warning records a Warning event on the Cluster.
+func (m *Manager) warning(reason, messageFmt string, args ...any) {
+	if m.recorder != nil {
+		m.recorder.Eventf(m.cluster, corev1.EventTypeWarning, reason, messageFmt, args...)
+	}
+}
+
+// contextEvent records the readiness of a context when it changes.
+func (m *Manager) contextEvent(name string, ready bool, reason, message string) {
+	message = fmt.Sprintf("context %s: %s", name, message)
+	if ready {
+		m.event(reason, "%s", message)
+		return
+	}
+	m.warning(reason, "%s", message)
+}
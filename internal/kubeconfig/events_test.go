@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/record"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("Events", func() {
+	var (
+		m        *Manager
+		recorder *record.FakeRecorder
+	)
+
+	ginkgo.BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		m = newTestManager()
+		m.recorder = recorder
+	})
+
+	ginkgo.It("should emit an event when a context becomes unreachable", func() {
+		m.cluster.Status.Clusters = kccnv1alpha1.ClusterStatusEntries{
+			"kind-tenant1": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+		}
+
+		m.setContextStatus(&Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{}}, "kind-tenant1",
+			kccnv1alpha1.ClusterStatusEntry{
+				Ready:   false,
+				Reason:  kccnv1alpha1.ReasonUnreachable,
+				Message: "connection refused",
+			})
+
+		Expect(recorder.Events).To(Receive(Equal(
+			"Warning Unreachable context kind-tenant1: connection refused")))
+	})
+
+	ginkgo.It("should not emit an event when nothing changed", func() {
+		m.cluster.Status.Clusters = kccnv1alpha1.ClusterStatusEntries{
+			"kind-tenant1": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+		}
+
+		m.setContextStatus(&Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{}}, "kind-tenant1",
+			kccnv1alpha1.ClusterStatusEntry{Ready: true, Reason: kccnv1alpha1.ReasonAvailable})
+
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	ginkgo.It("should emit an event when a secret is created", func() {
+		_, err := m.applySecret(desiredSecret{
+			namespace: "default",
+			name:      "kind-tenant1-kubeconfig",
+			labels:    m.ownerLabels(),
+			data:      map[string][]byte{DefaultSecretKey: []byte("config")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(recorder.Events).To(Receive(Equal(
+			"Normal SecretCreated created secret default/kind-tenant1-kubeconfig")))
+	})
+
+	ginkgo.It("should not fail without a recorder", func() {
+		m.recorder = nil
+		m.warning(EventReasonFirewallRulesRequired, "no recorder")
+	})
+})
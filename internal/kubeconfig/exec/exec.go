@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exec
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Name is the name of the exec passthrough provider.
+const Name = "exec"
+
+// Provider exports contexts authenticated by an exec plugin the operator
+// cannot mint credentials for, such as gke-gcloud-auth-plugin or tsh.
+//
+// The exec block is copied into the exported kubeconfig unchanged, so the
+// secret is only usable by consumers that ship the plugin binary and have
+// access to whatever state the plugin needs.
+type Provider struct{}
+
+var _ provider.Provider = Provider{}
+
+func (Provider) Name() string {
+	return Name
+}
+
+// Detect returns true if the user has an exec plugin. The provider is
+// registered last so that providers which mint credentials for specific
+// plugins take precedence.
+func (Provider) Detect(authInfo *api.AuthInfo) bool {
+	return authInfo.Exec != nil
+}
+
+// KubeConfig copies the exec block, and any client certificate alongside
+// it, into the exported kubeconfig.
+func (Provider) KubeConfig(_ context.Context, req provider.Request) (*api.Config, time.Time, error) {
+	if req.AuthInfo.Exec == nil {
+		return nil, time.Time{}, errors.Errorf("user %q does not use an exec plugin", req.User)
+	}
+
+	cfg := &api.Config{
+		APIVersion: api.SchemeGroupVersion.Version,
+		Clusters: map[string]*api.Cluster{
+			req.Context: {
+				Server:                   req.Config.Host,
+				CertificateAuthorityData: req.Config.CAData,
+				TLSServerName:            req.Config.ServerName,
+			},
+		},
+		Contexts: map[string]*api.Context{
+			req.Context: {
+				Cluster:  req.Context,
+				AuthInfo: req.User,
+			},
+		},
+		CurrentContext: req.Context,
+		AuthInfos: map[string]*api.AuthInfo{
+			req.User: {
+				Exec:                  req.AuthInfo.Exec.DeepCopy(),
+				ClientCertificateData: req.Config.CertData,
+				ClientKeyData:         req.Config.KeyData,
+			},
+		},
+	}
+
+	return cfg, time.Time{}, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package exec
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+var _ = ginkgo.Describe("Exec passthrough", func() {
+	request := func(authInfo *api.AuthInfo) provider.Request {
+		return provider.Request{
+			Context:  "gke",
+			Cluster:  "gke",
+			User:     "gke-user",
+			AuthInfo: authInfo,
+			Config: &rest.Config{
+				Host: "https://192.168.1.2:6443",
+				TLSClientConfig: rest.TLSClientConfig{
+					CAData:   []byte("ca"),
+					CertData: []byte("cert"),
+					KeyData:  []byte("key"),
+				},
+			},
+		}
+	}
+
+	ginkgo.It("should detect users with an exec plugin", func() {
+		Expect(Provider{}.Detect(&api.AuthInfo{Exec: &api.ExecConfig{Command: "tsh"}})).To(BeTrue())
+		Expect(Provider{}.Detect(&api.AuthInfo{Token: "abc"})).To(BeFalse())
+	})
+
+	ginkgo.It("should copy the exec plugin into the exported kubeconfig", func() {
+		authInfo := &api.AuthInfo{Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "gke-gcloud-auth-plugin",
+			Env:        []api.ExecEnvVar{{Name: "CLOUDSDK_CORE_PROJECT", Value: "dev"}},
+		}}
+
+		details, expiry, err := Provider{}.KubeConfig(context.Background(), request(authInfo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry.IsZero()).To(BeTrue())
+
+		user := details.AuthInfos["gke-user"]
+		Expect(user.Exec).To(Equal(authInfo.Exec))
+		Expect(user.Exec).NotTo(BeIdenticalTo(authInfo.Exec))
+		Expect(user.ClientCertificateData).To(Equal([]byte("cert")))
+		Expect(user.ClientKeyData).To(Equal([]byte("key")))
+		Expect(details.Clusters["gke"].Server).To(Equal("https://192.168.1.2:6443"))
+		Expect(details.Contexts["gke"].AuthInfo).To(Equal("gke-user"))
+	})
+
+	ginkgo.It("should not pass through users without an exec plugin", func() {
+		_, _, err := Provider{}.KubeConfig(context.Background(), request(&api.AuthInfo{Token: "abc"}))
+		Expect(err).To(MatchError(ContainSubstring("does not use an exec plugin")))
+	})
+})
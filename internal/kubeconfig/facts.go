@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// wellKnownAPIGroups are the API groups reported in the cluster facts
+// when installed.
+var wellKnownAPIGroups = []string{
+	"argoproj.io",
+	"cert-manager.io",
+	"gateway.networking.k8s.io",
+	"helm.toolkit.fluxcd.io",
+	"kustomize.toolkit.fluxcd.io",
+	"source.toolkit.fluxcd.io",
+}
+
+// nodePageSize is the number of nodes fetched per request when counting
+// nodes, so that large clusters are not listed in one go on every probe.
+const nodePageSize = 100
+
+// collectFacts gathers details about a reachable cluster. Facts that
+// cannot be collected, for example because the credentials may not list
+// nodes, are left unset rather than failing the probe.
+func collectFacts(
+	ctx context.Context, c client.Client, d discovery.DiscoveryInterface, info *version.Info,
+) *kccnv1alpha1.ClusterFacts {
+	facts := &kccnv1alpha1.ClusterFacts{}
+	if info != nil {
+		facts.KubernetesVersion = info.GitVersion
+		facts.Platform = info.Platform
+	}
+
+	facts.Nodes, facts.ReadyNodes = countNodes(ctx, c)
+
+	if groups, err := d.ServerGroups(); err == nil {
+		for _, group := range groups.Groups {
+			if slices.Contains(wellKnownAPIGroups, group.Name) {
+				facts.APIGroups = append(facts.APIGroups, group.Name)
+			}
+		}
+		slices.Sort(facts.APIGroups)
+	}
+
+	return facts
+}
+
+// countNodes returns the number of nodes and how many of them are ready,
+// a page at a time. Both are nil if the nodes could not be listed.
+func countNodes(ctx context.Context, c client.Client) (*int32, *int32) {
+	var total, ready int32
+
+	nodes := &corev1.NodeList{}
+	for {
+		if err := c.List(ctx, nodes, client.Limit(nodePageSize), client.Continue(nodes.Continue)); err != nil {
+			return nil, nil
+		}
+
+		total += int32(len(nodes.Items))
+		for _, node := range nodes.Items {
+			if nodeReady(node) {
+				ready++
+			}
+		}
+
+		if nodes.Continue == "" {
+			return &total, &ready
+		}
+	}
+}
+
+// nodeReady returns true if the node has a Ready condition of True.
+func nodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
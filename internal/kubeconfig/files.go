@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// inline returns data if set, otherwise the contents of the file at path.
+// Relative paths are resolved against the kubeconfig file the entry was
+// loaded from, and absolute paths are rewritten using the host path
+// mappings so that files referenced on the host can be read in the pod.
+//
+// Only files under the directory of the kubeconfig or a host path mapping
+// may be read, see allowedPath.
+func (o GetContextsOptions) inline(data []byte, path, origin string) ([]byte, error) {
+	if len(data) > 0 || path == "" {
+		return data, nil
+	}
+
+	resolved, err := o.allowedPath(path, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(resolved)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", resolved)
+	}
+
+	return content, nil
+}
+
+// allowedPath resolves a file referenced by a kubeconfig and checks that
+// it lies under the directory of the kubeconfig or the pod side of a host
+// path mapping, after following symlinks. Without this, a kubeconfig could
+// name any file in the operator pod, such as its service account token,
+// and have the contents copied into the exported secret.
+//
+// Kubeconfigs loaded from a Secret or ConfigMap may not reference files at
+// all, as whoever wrote them has no business reading files from the pod.
+func (o GetContextsOptions) allowedPath(path, origin string) (string, error) {
+	if o.inCluster {
+		return "", errors.Errorf("file %s cannot be referenced by a kubeconfig loaded from a Secret or ConfigMap", path)
+	}
+
+	resolved, err := filepath.Abs(o.resolvePath(path, origin))
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid path %s", path)
+	}
+
+	roots := []string{}
+	if dir := filepath.Dir(origin); origin != "" && dir != "/" {
+		roots = append(roots, dir)
+	}
+	for _, mapping := range o.pathMappings {
+		roots = append(roots, mapping.Pod)
+	}
+
+	target := evalSymlinks(resolved)
+	for _, root := range roots {
+		if within(target, evalSymlinks(root)) {
+			return target, nil
+		}
+	}
+
+	return "", errors.Errorf("file %s is outside the kubeconfig directory and hostPathMappings", path)
+}
+
+// evalSymlinks returns path with any symlinks followed. Paths that do not
+// exist are returned cleaned, as reading them fails anyway.
+func evalSymlinks(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	return filepath.Clean(path)
+}
+
+// within returns true if path is root or inside it.
+func within(path, root string) bool {
+	root = filepath.Clean(root)
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, "/")+"/")
+}
+
+// resolvePath returns the path to read a file referenced by a kubeconfig.
+func (o GetContextsOptions) resolvePath(path, origin string) string {
+	if !filepath.IsAbs(path) {
+		if origin == "" {
+			return path
+		}
+		return filepath.Join(filepath.Dir(origin), path)
+	}
+
+	path = filepath.Clean(path)
+
+	// The longest matching prefix wins so that more specific mappings can
+	// override broader ones.
+	var host, pod string
+	for _, mapping := range o.pathMappings {
+		prefix := filepath.Clean(mapping.Host)
+		if path != prefix && !strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		if len(prefix) > len(host) {
+			host, pod = prefix, filepath.Clean(mapping.Pod)
+		}
+	}
+
+	if host == "" {
+		return path
+	}
+
+	return filepath.Join(pod, strings.TrimPrefix(path, host))
+}
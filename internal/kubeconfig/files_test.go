@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("File referenced credentials", func() {
+	ginkgo.It("should resolve relative paths against the kubeconfig", func() {
+		options := GetContextsOptions{}
+		Expect(options.resolvePath("certs/ca.crt", "/tmp/kubeconfigs/config")).
+			To(Equal("/tmp/kubeconfigs/certs/ca.crt"))
+	})
+
+	ginkgo.It("should map host paths to pod paths", func() {
+		options := GetContextsOptions{pathMappings: []kccnv1alpha1.HostPathMapping{
+			{Host: "/home", Pod: "/host/home"},
+			{Host: "/home/me/.minikube", Pod: "/minikube"},
+		}}
+		Expect(options.resolvePath("/home/me/.kube/ca.crt", "")).To(Equal("/host/home/me/.kube/ca.crt"))
+		Expect(options.resolvePath("/home/me/.minikube/ca.crt", "")).To(Equal("/minikube/ca.crt"))
+		Expect(options.resolvePath("/homework/ca.crt", "")).To(Equal("/homework/ca.crt"))
+	})
+
+	ginkgo.It("should inline certificates referenced by file", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "ca.crt"), []byte("ca"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "client.crt"), []byte("cert"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "client.key"), []byte("key"), 0o600)).To(Succeed())
+
+		config := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: minikube
+  cluster:
+    server: https://127.0.0.1:8443
+    certificate-authority: ca.crt
+contexts:
+- name: minikube
+  context:
+    cluster: minikube
+    user: minikube
+users:
+- name: minikube
+  user:
+    client-certificate: client.crt
+    client-key: ` + filepath.Join("/host", dir, "client.key") + `
+`)
+		path := filepath.Join(dir, "config")
+		Expect(os.WriteFile(path, config, 0o600)).To(Succeed())
+
+		loaded, _, err := loadFiles([]string{path})
+		Expect(err).NotTo(HaveOccurred())
+
+		options := GetContextsOptions{
+			config:       loaded,
+			pathMappings: []kccnv1alpha1.HostPathMapping{{Host: "/host", Pod: "/"}},
+		}
+		c, err := ClientConfig("minikube", "192.168.1.2", options)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.CAData).To(Equal([]byte("ca")))
+		Expect(c.CertData).To(Equal([]byte("cert")))
+		Expect(c.KeyData).To(Equal([]byte("key")))
+	})
+
+	ginkgo.Describe("outside the allowed directories", func() {
+		var (
+			dir     string
+			origin  string
+			options GetContextsOptions
+		)
+
+		ginkgo.BeforeEach(func() {
+			dir = ginkgo.GinkgoT().TempDir()
+			origin = filepath.Join(dir, "kube", "config")
+			Expect(os.MkdirAll(filepath.Dir(origin), 0o700)).To(Succeed())
+			options = GetContextsOptions{pathMappings: []kccnv1alpha1.HostPathMapping{
+				{Host: "/home/me/.minikube", Pod: filepath.Join(dir, "minikube")},
+			}}
+		})
+
+		ginkgo.It("should refuse to read pod files", func() {
+			_, err := options.inline(nil, "/etc/passwd", origin)
+			Expect(err).To(MatchError(ContainSubstring("outside the kubeconfig directory")))
+
+			_, err = options.inline(nil, "../../../../../../etc/passwd", origin)
+			Expect(err).To(MatchError(ContainSubstring("outside the kubeconfig directory")))
+
+			_, err = options.inline(nil, "/home/me/.minikube/../../../etc/passwd", origin)
+			Expect(err).To(MatchError(ContainSubstring("outside the kubeconfig directory")))
+		})
+
+		ginkgo.It("should refuse symlinks that leave the kubeconfig directory", func() {
+			Expect(os.Symlink("/etc/passwd", filepath.Join(dir, "kube", "ca.crt"))).To(Succeed())
+
+			_, err := options.inline(nil, "ca.crt", origin)
+			Expect(err).To(MatchError(ContainSubstring("outside the kubeconfig directory")))
+		})
+
+		ginkgo.It("should read files under host path mappings", func() {
+			Expect(os.MkdirAll(filepath.Join(dir, "minikube"), 0o700)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "minikube", "ca.crt"), []byte("ca"), 0o600)).To(Succeed())
+
+			Expect(options.inline(nil, "/home/me/.minikube/ca.crt", origin)).To(Equal([]byte("ca")))
+		})
+
+		ginkgo.It("should refuse any file for kubeconfigs loaded from the cluster", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "kube", "ca.crt"), []byte("ca"), 0o600)).To(Succeed())
+
+			options.inCluster = true
+			_, err := options.inline(nil, "ca.crt", origin)
+			Expect(err).To(MatchError(ContainSubstring("loaded from a Secret or ConfigMap")))
+			_, err = options.inline(nil, "/etc/passwd", "")
+			Expect(err).To(MatchError(ContainSubstring("loaded from a Secret or ConfigMap")))
+
+			// Inline data is still accepted
+			Expect(options.inline([]byte("ca"), "", "")).To(Equal([]byte("ca")))
+		})
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+func TestKubeconfig(t *testing.T) {
+	RegisterFailHandler(ginkgo.Fail)
+
+	ginkgo.RunSpecs(t, "Kubeconfig Suite")
+}
+
+// newTestManager returns a Manager for the Cluster default/sample, with
+// the defaults the CRD would apply, backed by a fake client holding objs.
+func newTestManager(objs ...client.Object) *Manager {
+	return &Manager{
+		client:  fake.NewClientBuilder().WithObjects(objs...).Build(),
+		context: context.Background(),
+		log:     logr.Discard(),
+		cluster: &kccnv1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"},
+			Spec:       kccnv1alpha1.ClusterSpec{NamespacePrefix: "cluster"},
+		},
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// Paths returns the kubeconfig files and globs a Cluster is loaded from,
+// in order of precedence. It is empty when the kubeconfig is read from a
+// Secret or ConfigMap.
+func Paths(cluster *kccnv1alpha1.Cluster) []string {
+	if cluster.Spec.KubeConfigSource != nil {
+		return nil
+	}
+
+	paths := []string{}
+	if cluster.Spec.KubeConfigPath != "" {
+		paths = append(paths, cluster.Spec.KubeConfigPath)
+	}
+	for _, path := range cluster.Spec.KubeConfigPaths {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if glob := dirGlob(cluster.Spec.KubeConfigDir); glob != "" {
+		paths = append(paths, glob)
+	}
+
+	return paths
+}
+
+// dirGlob turns a plain directory into a glob matching every file in it.
+func dirGlob(dir string) string {
+	if dir == "" {
+		return ""
+	}
+
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return filepath.Join(dir, "*")
+	}
+
+	return dir
+}
+
+// files expands KubeConfigDir and returns the files to merge. Files
+// named explicitly must exist, and KubeConfigDir must match at least one
+// file, so that a missing mount is never mistaken for every context having
+// been removed.
+func (m *Manager) files() ([]string, error) {
+	files := []string{}
+	seen := make(map[string]bool)
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	explicit := append([]string{m.cluster.Spec.KubeConfigPath}, m.cluster.Spec.KubeConfigPaths...)
+	for _, path := range explicit {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, errors.Wrap(err, "kubeconfig not readable")
+		}
+		add(path)
+	}
+
+	if glob := dirGlob(m.cluster.Spec.KubeConfigDir); glob != "" {
+		matches, err := filepath.Glob(glob)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid kubeConfigDir %q", m.cluster.Spec.KubeConfigDir)
+		}
+		sort.Strings(matches)
+
+		found := 0
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				add(match)
+				found++
+			}
+		}
+		if found == 0 {
+			return nil, errors.Errorf("kubeConfigDir %q does not exist or contains no files", m.cluster.Spec.KubeConfigDir)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no kubeconfig configured, set kubeConfigPath, kubeConfigPaths, kubeConfigDir or kubeConfigSource")
+	}
+
+	return files, nil
+}
+
+// loadFiles merges the kubeconfig files using the same precedence as
+// client-go: the first file to define an entry wins. Entries that a later
+// file defines differently are returned as conflicts.
+func loadFiles(files []string) (*api.Config, []kccnv1alpha1.KubeConfigConflict, error) {
+	merged := api.NewConfig()
+	conflicts := newConflicts()
+
+	for _, file := range files {
+		config, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to load kubeconfig %s", file)
+		}
+
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = config.CurrentContext
+		}
+
+		for name, ctx := range config.Contexts {
+			if existing, ok := merged.Contexts[name]; ok {
+				conflicts.check(kccnv1alpha1.ConflictKindContext, name, existing, ctx)
+				continue
+			}
+			merged.Contexts[name] = ctx
+		}
+
+		for name, cluster := range config.Clusters {
+			if existing, ok := merged.Clusters[name]; ok {
+				conflicts.check(kccnv1alpha1.ConflictKindCluster, name, existing, cluster)
+				continue
+			}
+			merged.Clusters[name] = cluster
+		}
+
+		for name, user := range config.AuthInfos {
+			if existing, ok := merged.AuthInfos[name]; ok {
+				conflicts.check(kccnv1alpha1.ConflictKindUser, name, existing, user)
+				continue
+			}
+			merged.AuthInfos[name] = user
+		}
+	}
+
+	return merged, conflicts.list(), nil
+}
+
+type conflicts struct {
+	entries map[string]*kccnv1alpha1.KubeConfigConflict
+}
+
+func newConflicts() *conflicts {
+	return &conflicts{entries: make(map[string]*kccnv1alpha1.KubeConfigConflict)}
+}
+
+// check records a conflict if the entry differs from the one already
+// merged. Both entries are one of api.Context, api.Cluster or
+// api.AuthInfo, which carry the file they were loaded from.
+func (c *conflicts) check(kind, name string, existing, ignored any) {
+	used, usedFile := withoutOrigin(existing)
+	other, otherFile := withoutOrigin(ignored)
+	if reflect.DeepEqual(used, other) {
+		return
+	}
+
+	key := kind + "/" + name
+	conflict, ok := c.entries[key]
+	if !ok {
+		conflict = &kccnv1alpha1.KubeConfigConflict{
+			Kind:    kind,
+			Name:    name,
+			File:    usedFile,
+			Ignored: []string{},
+		}
+		c.entries[key] = conflict
+	}
+	conflict.Ignored = append(conflict.Ignored, otherFile)
+}
+
+func (c *conflicts) list() []kccnv1alpha1.KubeConfigConflict {
+	list := make([]kccnv1alpha1.KubeConfigConflict, 0, len(c.entries))
+	for _, conflict := range c.entries {
+		list = append(list, *conflict)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Kind != list[j].Kind {
+			return list[i].Kind < list[j].Kind
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// withoutOrigin returns a copy of entry with LocationOfOrigin cleared so
+// that entries can be compared, along with the original location.
+func withoutOrigin(entry any) (any, string) {
+	switch e := entry.(type) {
+	case *api.Context:
+		c := *e
+		c.LocationOfOrigin = ""
+		return c, e.LocationOfOrigin
+	case *api.Cluster:
+		c := *e
+		c.LocationOfOrigin = ""
+		return c, e.LocationOfOrigin
+	case *api.AuthInfo:
+		c := *e
+		c.LocationOfOrigin = ""
+		return c, e.LocationOfOrigin
+	}
+
+	return entry, ""
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+const mergeKubeconfig = `apiVersion: v1
+kind: Config
+current-context: %[1]s
+clusters:
+- name: %[1]s
+  cluster:
+    server: %[2]s
+contexts:
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s
+users:
+- name: %[1]s
+  user:
+    token: abc
+`
+
+var _ = ginkgo.Describe("Merging kubeconfig files", func() {
+	var (
+		m   *Manager
+		dir string
+	)
+
+	write := func(file, name, server string) string {
+		path := filepath.Join(dir, file)
+		content := []byte(fmt.Sprintf(mergeKubeconfig, name, server))
+		Expect(os.WriteFile(path, content, 0o600)).To(Succeed())
+		return path
+	}
+
+	ginkgo.BeforeEach(func() {
+		dir = ginkgo.GinkgoT().TempDir()
+		m = newTestManager()
+	})
+
+	ginkgo.It("should merge every file in a directory", func() {
+		write("a.yaml", "kind-a", "https://192.168.1.2:6443")
+		write("b.yaml", "kind-b", "https://192.168.1.3:6443")
+		m.cluster.Spec.KubeConfigDir = dir
+
+		options, err := m.getOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.config.Contexts).To(HaveKey("kind-a"))
+		Expect(options.config.Contexts).To(HaveKey("kind-b"))
+		Expect(options.config.CurrentContext).To(Equal("kind-a"))
+		Expect(options.conflicts).To(BeEmpty())
+	})
+
+	ginkgo.It("should prefer the first file and report conflicts", func() {
+		first := write("first", "kind-a", "https://192.168.1.2:6443")
+		second := write("second", "kind-a", "https://192.168.1.3:6443")
+		m.cluster.Spec.KubeConfigPaths = []string{first, second}
+
+		options, err := m.getOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.config.Clusters["kind-a"].Server).To(Equal("https://192.168.1.2:6443"))
+		Expect(options.conflicts).To(ConsistOf(kccnv1alpha1.KubeConfigConflict{
+			Kind:    kccnv1alpha1.ConflictKindCluster,
+			Name:    "kind-a",
+			File:    first,
+			Ignored: []string{second},
+		}))
+	})
+
+	ginkgo.It("should not report identical entries as conflicts", func() {
+		first := write("first", "kind-a", "https://192.168.1.2:6443")
+		second := write("second", "kind-a", "https://192.168.1.2:6443")
+		m.cluster.Spec.KubeConfigPaths = []string{first, second}
+
+		options, err := m.getOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.conflicts).To(BeEmpty())
+	})
+
+	ginkgo.It("should fail when a listed file is missing", func() {
+		first := write("first", "kind-a", "https://192.168.1.2:6443")
+		m.cluster.Spec.KubeConfigPaths = []string{first, filepath.Join(dir, "missing")}
+
+		_, err := m.getOptions()
+		Expect(err).To(HaveOccurred())
+	})
+
+	ginkgo.It("should fail when the directory is missing or empty", func() {
+		m.cluster.Spec.KubeConfigDir = filepath.Join(dir, "missing")
+		_, err := m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring("does not exist or contains no files")))
+
+		m.cluster.Spec.KubeConfigDir = dir
+		_, err = m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring("does not exist or contains no files")))
+
+		m.cluster.Spec.KubeConfigDir = filepath.Join(dir, "*.yaml")
+		write("config", "kind-a", "https://192.168.1.2:6443")
+		_, err = m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring("does not exist or contains no files")))
+	})
+
+	ginkgo.It("should fail when no kubeconfig is configured", func() {
+		_, err := m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring("no kubeconfig configured")))
+	})
+
+	ginkgo.It("should not prune when the kubeconfig has no contexts", func() {
+		path := filepath.Join(dir, "config")
+		Expect(os.WriteFile(path, []byte("apiVersion: v1\nkind: Config\n"), 0o600)).To(Succeed())
+		m.cluster.Spec.KubeConfigPath = path
+
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "kind-a-kubeconfig",
+			Namespace:   "cluster-kind-a",
+			Labels:      m.ownerLabels(),
+			Annotations: map[string]string{AnnotationContext: "kind-a"},
+		}}
+		Expect(m.client.Create(m.context, secret)).To(Succeed())
+		ginkgo.DeferCleanup(m.deleteMetrics)
+
+		status, err := m.ReconcileKubeconfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Pruned).To(BeEmpty())
+		Expect(m.client.Get(m.context, client.ObjectKeyFromObject(secret), &corev1.Secret{})).To(Succeed())
+	})
+
+	ginkgo.It("should return paths in order of precedence", func() {
+		m.cluster.Spec.KubeConfigPath = "/tmp/kubeconfig"
+		m.cluster.Spec.KubeConfigPaths = []string{"/tmp/other"}
+		m.cluster.Spec.KubeConfigDir = dir
+
+		Expect(Paths(m.cluster)).To(Equal([]string{
+			"/tmp/kubeconfig", "/tmp/other", filepath.Join(dir, "*"),
+		}))
+	})
+})
@@ -0,0 +1,210 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const metricsNamespace = "kubeconfig_operator"
+
+// Values of the type label on the credential expiry metric.
+const (
+	credentialTypeToken       = "token"
+	credentialTypeCertificate = "certificate"
+)
+
+var (
+	contextsDiscovered = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "contexts_discovered",
+		Help:      "Number of contexts selected from the kubeconfig of a Cluster.",
+	}, []string{"namespace", "name"})
+
+	contextsReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "contexts",
+		Help:      "Number of contexts of a Cluster by readiness.",
+	}, []string{"namespace", "name", "ready"})
+
+	probeDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "probe_duration_seconds",
+		Help:      "Latency of the /version request made when probing a context.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"namespace", "name", "context"})
+
+	contextFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "context_failures_total",
+		Help:      "Number of times a context became not ready, by reason.",
+	}, []string{"namespace", "name", "context", "reason"})
+
+	secretWrites = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "secret_writes_total",
+		Help:      "Number of times the data of a kubeconfig secret was written.",
+	}, []string{"namespace", "name", "operation"})
+
+	credentialExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Name:      "credential_expiry_timestamp_seconds",
+		Help:      "Unix time at which the exported credentials of a context expire.",
+	}, []string{"namespace", "name", "context", "type"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		contextsDiscovered,
+		contextsReady,
+		probeDuration,
+		contextFailures,
+		secretWrites,
+		credentialExpiry,
+	)
+}
+
+// clusterLabels are the labels identifying the Cluster on every metric.
+func (m *Manager) clusterLabels() prometheus.Labels {
+	return prometheus.Labels{"namespace": m.cluster.Namespace, "name": m.cluster.Name}
+}
+
+// resetMetrics removes the per context series of the Cluster so that
+// contexts that have gone away are no longer reported. The probe latency
+// and failure count of a context are kept for as long as it is selected.
+func (m *Manager) resetMetrics(contexts []Context) {
+	contextsReady.DeletePartialMatch(m.clusterLabels())
+	credentialExpiry.DeletePartialMatch(m.clusterLabels())
+
+	selected := make(map[string]bool, len(contexts))
+	for _, ctx := range contexts {
+		selected[ctx.name] = true
+	}
+	for name := range m.cluster.Status.Clusters {
+		if selected[name] {
+			continue
+		}
+		labels := m.clusterLabels()
+		labels["context"] = name
+		probeDuration.DeletePartialMatch(labels)
+		contextFailures.DeletePartialMatch(labels)
+	}
+}
+
+// recordLoadFailure reports no ready or unready contexts while the
+// kubeconfig of the Cluster cannot be loaded.
+func (m *Manager) recordLoadFailure() {
+	contextsReady.WithLabelValues(m.cluster.Namespace, m.cluster.Name, "true").Set(0)
+	contextsReady.WithLabelValues(m.cluster.Namespace, m.cluster.Name, "false").Set(0)
+}
+
+// deleteMetrics removes every series of the Cluster.
+func (m *Manager) deleteMetrics() {
+	labels := m.clusterLabels()
+	contextsDiscovered.DeletePartialMatch(labels)
+	contextsReady.DeletePartialMatch(labels)
+	probeDuration.DeletePartialMatch(labels)
+	contextFailures.DeletePartialMatch(labels)
+	secretWrites.DeletePartialMatch(labels)
+	credentialExpiry.DeletePartialMatch(labels)
+}
+
+// recordStatus records the readiness of every context once the Cluster
+// has been reconciled.
+func (m *Manager) recordStatus(status *Status, discovered int) {
+	labels := m.clusterLabels()
+	contextsDiscovered.With(labels).Set(float64(discovered))
+
+	ready, unready := 0, 0
+	for _, entry := range status.ClusterStatus {
+		if entry.Ready {
+			ready++
+			continue
+		}
+		unready++
+	}
+
+	contextsReady.WithLabelValues(m.cluster.Namespace, m.cluster.Name, "true").Set(float64(ready))
+	contextsReady.WithLabelValues(m.cluster.Namespace, m.cluster.Name, "false").Set(float64(unready))
+}
+
+// recordFailure counts a context becoming not ready, or changing the
+// reason it is not ready for.
+func (m *Manager) recordFailure(context, reason string) {
+	contextFailures.WithLabelValues(m.cluster.Namespace, m.cluster.Name, context, reason).Inc()
+}
+
+// recordProbe records the latency of a probe that reached the server.
+func (m *Manager) recordProbe(context string, latency time.Duration) {
+	if latency > 0 {
+		probeDuration.WithLabelValues(m.cluster.Namespace, m.cluster.Name, context).Observe(latency.Seconds())
+	}
+}
+
+// recordSecretWrite counts a write of secret data.
+func (m *Manager) recordSecretWrite(operation string) {
+	secretWrites.WithLabelValues(m.cluster.Namespace, m.cluster.Name, operation).Inc()
+}
+
+// recordExpiry records when the token and client certificate of a
+// context expire.
+func (m *Manager) recordExpiry(context string, details *api.Config, tokenExpiry time.Time) {
+	if !tokenExpiry.IsZero() {
+		credentialExpiry.WithLabelValues(m.cluster.Namespace, m.cluster.Name, context, credentialTypeToken).
+			Set(float64(tokenExpiry.Unix()))
+	}
+
+	if expiry := certificateExpiry(details); !expiry.IsZero() {
+		credentialExpiry.WithLabelValues(m.cluster.Namespace, m.cluster.Name, context, credentialTypeCertificate).
+			Set(float64(expiry.Unix()))
+	}
+}
+
+// certificateExpiry returns the earliest expiry of the client
+// certificates in the config, or the zero time if there are none.
+func certificateExpiry(details *api.Config) (expiry time.Time) {
+	for _, authInfo := range details.AuthInfos {
+		rest := authInfo.ClientCertificateData
+		for {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if expiry.IsZero() || cert.NotAfter.Before(expiry) {
+				expiry = cert.NotAfter
+			}
+
+			// Only the leaf certificate identifies the client
+			break
+		}
+	}
+
+	return
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// clientCertificate returns a PEM encoded self signed certificate that
+// expires at notAfter.
+func clientCertificate(notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kind-tenant1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+var _ = ginkgo.Describe("Metrics", func() {
+	var m *Manager
+
+	ginkgo.BeforeEach(func() {
+		// A name of its own keeps the series apart from other tests.
+		m = newTestManager()
+		m.cluster.Name = "metrics"
+	})
+
+	ginkgo.AfterEach(func() {
+		m.deleteMetrics()
+	})
+
+	ginkgo.It("should find the expiry of a client certificate", func() {
+		notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+		details := api.NewConfig()
+		details.AuthInfos["kind-tenant1"] = &api.AuthInfo{ClientCertificateData: clientCertificate(notAfter)}
+
+		Expect(certificateExpiry(details)).To(BeTemporally("==", notAfter))
+		Expect(certificateExpiry(api.NewConfig()).IsZero()).To(BeTrue())
+	})
+
+	ginkgo.It("should record when credentials expire", func() {
+		details := api.NewConfig()
+		details.AuthInfos["kind-tenant1"] = &api.AuthInfo{
+			ClientCertificateData: clientCertificate(time.Now().Add(time.Hour)),
+		}
+		tokenExpiry := time.Now().Add(10 * time.Minute)
+		m.recordExpiry("kind-tenant1", details, tokenExpiry)
+
+		token := credentialExpiry.WithLabelValues("default", "metrics", "kind-tenant1", credentialTypeToken)
+		Expect(testutil.ToFloat64(token)).To(Equal(float64(tokenExpiry.Unix())))
+		cert := credentialExpiry.WithLabelValues("default", "metrics", "kind-tenant1", credentialTypeCertificate)
+		Expect(testutil.ToFloat64(cert)).To(BeNumerically("~", time.Now().Add(time.Hour).Unix(), 5))
+	})
+
+	ginkgo.It("should record ready and unready contexts", func() {
+		status := &Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{
+			"a": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+			"b": {Ready: false, Reason: kccnv1alpha1.ReasonUnreachable},
+		}}
+		m.recordStatus(status, 3)
+
+		Expect(testutil.ToFloat64(contextsDiscovered.WithLabelValues("default", "metrics"))).To(Equal(3.0))
+		Expect(testutil.ToFloat64(contextsReady.WithLabelValues("default", "metrics", "true"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(contextsReady.WithLabelValues("default", "metrics", "false"))).To(Equal(1.0))
+
+		m.resetMetrics(nil)
+		Expect(testutil.CollectAndCount(contextsReady)).To(Equal(0))
+	})
+
+	ginkgo.It("should remove the series of contexts that are no longer selected", func() {
+		m.cluster.Status.Clusters = kccnv1alpha1.ClusterStatusEntries{
+			"a": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+			"b": {Ready: false, Reason: kccnv1alpha1.ReasonUnreachable},
+		}
+		for _, context := range []string{"a", "b"} {
+			m.recordProbe(context, 10*time.Millisecond)
+			m.recordFailure(context, kccnv1alpha1.ReasonUnreachable)
+		}
+
+		m.resetMetrics([]Context{{name: "a"}})
+
+		// Deleting a series reports whether it was still there.
+		Expect(probeDuration.DeleteLabelValues("default", "metrics", "b")).To(BeFalse())
+		Expect(contextFailures.DeleteLabelValues("default", "metrics", "b", kccnv1alpha1.ReasonUnreachable)).To(BeFalse())
+		Expect(probeDuration.DeleteLabelValues("default", "metrics", "a")).To(BeTrue())
+		Expect(contextFailures.DeleteLabelValues("default", "metrics", "a", kccnv1alpha1.ReasonUnreachable)).To(BeTrue())
+	})
+
+	ginkgo.It("should report no ready contexts when the kubeconfig fails to load", func() {
+		m.cluster.Spec.KubeConfigPath = "/nonexistent/config"
+		m.recordStatus(&Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{
+			"a": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+			"b": {Ready: false, Reason: kccnv1alpha1.ReasonUnreachable},
+		}}, 2)
+
+		_, err := m.ReconcileKubeconfig()
+		Expect(err).To(HaveOccurred())
+		Expect(testutil.ToFloat64(contextsReady.WithLabelValues("default", "metrics", "true"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(contextsReady.WithLabelValues("default", "metrics", "false"))).To(Equal(0.0))
+	})
+
+	ginkgo.It("should only count failures when a context becomes not ready", func() {
+		failures := contextFailures.WithLabelValues("default", "metrics", "b", kccnv1alpha1.ReasonUnreachable)
+		unreachable := kccnv1alpha1.ClusterStatusEntry{Ready: false, Reason: kccnv1alpha1.ReasonUnreachable}
+
+		for range 3 {
+			status := &Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{}}
+			m.setContextStatus(status, "b", unreachable)
+			m.cluster.Status.Clusters = status.ClusterStatus
+		}
+		Expect(testutil.ToFloat64(failures)).To(Equal(1.0))
+
+		m.cluster.Status.Clusters = kccnv1alpha1.ClusterStatusEntries{
+			"b": {Ready: true, Reason: kccnv1alpha1.ReasonAvailable},
+		}
+		m.setContextStatus(&Status{ClusterStatus: kccnv1alpha1.ClusterStatusEntries{}}, "b", unreachable)
+		Expect(testutil.ToFloat64(failures)).To(Equal(2.0))
+	})
+})
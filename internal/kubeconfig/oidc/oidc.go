@@ -0,0 +1,361 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Name is the name of the OIDC provider.
+const Name = "oidc"
+
+// requestTimeout bounds each request made to the issuer.
+const requestTimeout = 30 * time.Second
+
+// sessionIdleTimeout is how long the tokens for a user are kept after they
+// were last asked for. It is well above any sensible reconcile interval so
+// that a rotated refresh token is not lost between reconciliations.
+const sessionIdleTimeout = 24 * time.Hour
+
+// Provider exports contexts authenticated with OIDC, either through the
+// kubelogin exec plugin or the legacy `auth-provider: oidc` block. The
+// refresh token is exchanged with the issuer for an ID token which is
+// exported as a bearer token, and re-issued before it expires.
+//
+// Issuers that rotate refresh tokens invalidate the one in the kubeconfig
+// on first use. The latest refresh token is kept in memory so that
+// following refreshes succeed, but is lost when the operator restarts.
+type Provider struct {
+	// RefreshBefore is how long before expiry an ID token is re-issued.
+	RefreshBefore time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session is the latest tokens issued for a user. Each session is locked
+// separately so that a slow issuer only holds up its own users.
+type session struct {
+	mu           sync.Mutex
+	lastUsed     time.Time
+	idToken      string
+	expiry       time.Time
+	refreshToken string
+}
+
+var _ provider.Provider = &Provider{}
+
+// NewProvider creates an OIDC provider that re-issues ID tokens
+// refreshBefore they expire.
+func NewProvider(refreshBefore time.Duration) *Provider {
+	return &Provider{
+		RefreshBefore: refreshBefore,
+		sessions:      make(map[string]*session),
+	}
+}
+
+func (p *Provider) Name() string {
+	return Name
+}
+
+// Detect returns true for the kubelogin exec plugin and the legacy oidc
+// auth provider.
+func (p *Provider) Detect(authInfo *api.AuthInfo) bool {
+	return isKubelogin(authInfo.Exec) || isAuthProvider(authInfo.AuthProvider)
+}
+
+// KubeConfig exports a fresh ID token for the user. Kubeconfigs loaded from
+// the cluster are refused, as the issuer they name would be contacted from
+// the operator pod on behalf of whoever wrote them.
+func (p *Provider) KubeConfig(ctx context.Context, req provider.Request) (*api.Config, time.Time, error) {
+	if req.InCluster {
+		return nil, time.Time{}, errors.New("oidc is not supported for kubeconfigs loaded from a Secret or ConfigMap")
+	}
+
+	s, err := p.settings(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	token, expiry, err := p.token(ctx, s)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	cfg := &api.Config{
+		APIVersion: api.SchemeGroupVersion.Version,
+		Clusters: map[string]*api.Cluster{
+			req.Context: {
+				Server:                   req.Config.Host,
+				CertificateAuthorityData: req.Config.CAData,
+				TLSServerName:            req.Config.ServerName,
+			},
+		},
+		Contexts: map[string]*api.Context{
+			req.Context: {
+				Cluster:  req.Context,
+				AuthInfo: req.User,
+			},
+		},
+		CurrentContext: req.Context,
+		AuthInfos: map[string]*api.AuthInfo{
+			req.User: {
+				Token: token,
+			},
+		},
+	}
+
+	return cfg, expiry, nil
+}
+
+// settings reads the OIDC settings for the user, including tokens from
+// the kubelogin cache.
+func (p *Provider) settings(req provider.Request) (settings, error) {
+	var (
+		s   settings
+		err error
+	)
+
+	origin := req.AuthInfo.LocationOfOrigin
+	switch {
+	case isAuthProvider(req.AuthInfo.AuthProvider):
+		s, err = settingsFromAuthProvider(req.AuthInfo.AuthProvider.Config)
+	case isKubelogin(req.AuthInfo.Exec):
+		s, err = settingsFromKubelogin(req.AuthInfo.Exec)
+		if err != nil {
+			break
+		}
+
+		var (
+			dir    string
+			cached cachedToken
+		)
+		if dir, err = req.ResolvePath(cacheDir(s.cacheDir, origin)); err != nil {
+			break
+		}
+		cached, err = fromKubeloginCache(dir, s.issuerURL, s.clientID)
+		s.refreshToken, s.idToken = cached.RefreshToken, cached.IDToken
+	default:
+		return s, errors.New("user does not use oidc")
+	}
+	if err != nil {
+		return s, err
+	}
+
+	if s.issuerURL == "" || s.clientID == "" {
+		return s, errors.New("oidc issuer url and client id are required")
+	}
+
+	if s.caFile != "" {
+		if s.caFile, err = req.ResolvePath(s.caFile); err != nil {
+			return s, errors.Wrap(err, "cannot read oidc issuer certificate authority")
+		}
+	}
+
+	return s, nil
+}
+
+// cacheDir returns where to read the kubelogin token cache from. By
+// default kubelogin caches tokens in ~/.kube/cache/oidc-login, so ~ is
+// taken to be the parent of the directory holding the kubeconfig.
+func cacheDir(dir, origin string) string {
+	home := filepath.Dir(filepath.Dir(origin))
+	switch {
+	case dir == "":
+		return filepath.Join(filepath.Dir(origin), "cache", "oidc-login")
+	case strings.HasPrefix(dir, "~/"):
+		return filepath.Join(home, strings.TrimPrefix(dir, "~/"))
+	}
+
+	return dir
+}
+
+// token returns a valid ID token, refreshing it with the issuer when it is
+// missing or due to expire.
+func (p *Provider) token(ctx context.Context, s settings) (string, time.Time, error) {
+	current := p.session(s)
+	current.mu.Lock()
+	defer current.mu.Unlock()
+
+	if current.idToken != "" && time.Until(current.expiry) > p.RefreshBefore {
+		return current.idToken, current.expiry, nil
+	}
+
+	if current.refreshToken == "" {
+		return "", time.Time{}, errors.New("no oidc refresh token available, log in again with kubectl")
+	}
+
+	refreshed, err := refresh(ctx, s, current.refreshToken)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	current.idToken, current.expiry, current.refreshToken =
+		refreshed.idToken, refreshed.expiry, refreshed.refreshToken
+	return current.idToken, current.expiry, nil
+}
+
+// session returns the session for the user, creating it from the tokens
+// in the kubeconfig if there is none. Sessions that have not been used
+// for sessionIdleTimeout are dropped.
+func (p *Provider) session(s settings) *session {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for key, idle := range p.sessions {
+		if now.Sub(idle.lastUsed) > sessionIdleTimeout {
+			delete(p.sessions, key)
+		}
+	}
+
+	key := strings.Join([]string{s.issuerURL, s.clientID, s.refreshToken}, "\x00")
+	current, ok := p.sessions[key]
+	if !ok {
+		current = &session{refreshToken: s.refreshToken}
+		if c, err := parseClaims(s.idToken); err == nil {
+			current.idToken, current.expiry = s.idToken, c.expiry()
+		}
+		p.sessions[key] = current
+	}
+	current.lastUsed = now
+
+	return current
+}
+
+// refresh performs the refresh token grant against the issuer.
+func refresh(ctx context.Context, s settings, refreshToken string) (*session, error) {
+	client, err := httpClient(s)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+
+	tokenURL, err := tokenEndpoint(ctx, client, s.issuerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &oauth2.Config{
+		ClientID:     s.clientID,
+		ClientSecret: s.clientSecret,
+		Endpoint:     oauth2.Endpoint{TokenURL: tokenURL},
+		Scopes:       append([]string{"openid"}, s.scopes...),
+	}
+
+	token, err := config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to refresh oidc token")
+	}
+
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		return nil, errors.New("oidc issuer did not return an id token")
+	}
+
+	c, err := parseClaims(idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshed := &session{
+		idToken:      idToken,
+		expiry:       c.expiry(),
+		refreshToken: refreshToken,
+	}
+	if refreshed.expiry.IsZero() {
+		refreshed.expiry = token.Expiry
+	}
+	if token.RefreshToken != "" {
+		refreshed.refreshToken = token.RefreshToken
+	}
+
+	return refreshed, nil
+}
+
+// tokenEndpoint reads the token endpoint from the issuer discovery
+// document.
+func tokenEndpoint(ctx context.Context, client *http.Client, issuer string) (string, error) {
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid oidc issuer url")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch oidc discovery document")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("oidc discovery returned %s", resp.Status)
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return "", errors.Wrap(err, "failed to parse oidc discovery document")
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", errors.New("oidc discovery document has no token_endpoint")
+	}
+
+	return discovery.TokenEndpoint, nil
+}
+
+// httpClient returns a client trusting the issuer CA, if one is set.
+func httpClient(s settings) (*http.Client, error) {
+	ca := s.caData
+	if len(ca) == 0 && s.caFile != "" {
+		var err error
+		if ca, err = os.ReadFile(s.caFile); err != nil {
+			return nil, errors.Wrap(err, "failed to read oidc issuer certificate authority")
+		}
+	}
+
+	if len(ca) == 0 {
+		return &http.Client{Timeout: requestTimeout}, nil
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, errors.New("oidc issuer certificate authority contains no valid certificates")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+
+	return &http.Client{Timeout: requestTimeout, Transport: transport}, nil
+}
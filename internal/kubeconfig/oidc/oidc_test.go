@@ -0,0 +1,231 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// refreshBefore is how long before expiry the provider under test
+// refreshes id tokens.
+const refreshBefore = 5 * time.Minute
+
+// idToken returns an unsigned JWT with the given claims.
+func idToken(issuer, audience string, expiry time.Time) string {
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		Expect(err).NotTo(HaveOccurred())
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	return encode(map[string]string{"alg": "none"}) + "." +
+		encode(map[string]any{"iss": issuer, "aud": audience, "exp": expiry.Unix()}) + ".sig"
+}
+
+// issuer is a local stand in for an OIDC issuer that supports the refresh
+// token grant and rotates refresh tokens.
+type issuer struct {
+	*httptest.Server
+	refreshes    atomic.Int32
+	refreshToken string
+	lifetime     time.Duration
+}
+
+func newIssuer() *issuer {
+	i := &issuer{refreshToken: "refresh-1", lifetime: time.Hour}
+	i.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			_, _ = fmt.Fprintf(w, `{"issuer":%q,"token_endpoint":%q}`, i.URL, i.URL+"/token")
+		case "/token":
+			_ = r.ParseForm()
+			if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != i.refreshToken {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+
+			n := i.refreshes.Add(1)
+			i.refreshToken = fmt.Sprintf("refresh-%d", n+1)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token":  "access",
+				"token_type":    "Bearer",
+				"expires_in":    int(i.lifetime.Seconds()),
+				"id_token":      idToken(i.URL, "kubernetes", time.Now().Add(i.lifetime)),
+				"refresh_token": i.refreshToken,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return i
+}
+
+var _ = ginkgo.Describe("OIDC provider", func() {
+	var (
+		idp *issuer
+		p   *Provider
+	)
+
+	ginkgo.BeforeEach(func() {
+		idp = newIssuer()
+		p = NewProvider(refreshBefore)
+	})
+
+	ginkgo.AfterEach(func() {
+		idp.Close()
+	})
+
+	request := func(authInfo *api.AuthInfo) provider.Request {
+		return provider.Request{
+			Context:     "dev",
+			Cluster:     "dev",
+			User:        "dev",
+			AuthInfo:    authInfo,
+			Config:      &rest.Config{Host: "https://192.168.1.2:6443"},
+			ResolvePath: func(path string) (string, error) { return path, nil },
+		}
+	}
+
+	legacy := func() *api.AuthInfo {
+		return &api.AuthInfo{AuthProvider: &api.AuthProviderConfig{
+			Name: "oidc",
+			Config: map[string]string{
+				"idp-issuer-url": idp.URL,
+				"client-id":      "kubernetes",
+				"client-secret":  "secret",
+				"refresh-token":  "refresh-1",
+			},
+		}}
+	}
+
+	ginkgo.It("should be detected for oidc users", func() {
+		Expect(p.Detect(legacy())).To(BeTrue())
+		Expect(p.Detect(&api.AuthInfo{Exec: &api.ExecConfig{
+			Command: "kubectl", Args: []string{"oidc-login", "get-token", "--oidc-issuer-url=https://idp"},
+		}})).To(BeTrue())
+		Expect(p.Detect(&api.AuthInfo{Exec: &api.ExecConfig{
+			Command: "kubelogin", Args: []string{"get-token", "--oidc-issuer-url", "https://idp"},
+		}})).To(BeTrue())
+		Expect(p.Detect(&api.AuthInfo{Exec: &api.ExecConfig{Command: "kubelogin"}})).To(BeFalse())
+		Expect(p.Detect(&api.AuthInfo{Token: "abc"})).To(BeFalse())
+	})
+
+	ginkgo.It("should exchange the refresh token for an id token", func() {
+		details, expiry, err := p.KubeConfig(context.Background(), request(legacy()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idp.refreshes.Load()).To(BeEquivalentTo(1))
+		Expect(details.AuthInfos["dev"].Token).To(HavePrefix("eyJ"))
+		Expect(details.AuthInfos["dev"].AuthProvider).To(BeNil())
+		Expect(expiry).To(BeTemporally("~", time.Now().Add(time.Hour), 5*time.Second))
+
+		// The id token is reused until it is due to expire.
+		_, _, err = p.KubeConfig(context.Background(), request(legacy()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idp.refreshes.Load()).To(BeEquivalentTo(1))
+	})
+
+	ginkgo.It("should re-issue tokens before they expire using the rotated refresh token", func() {
+		idp.lifetime = refreshBefore / 2
+
+		_, _, err := p.KubeConfig(context.Background(), request(legacy()))
+		Expect(err).NotTo(HaveOccurred())
+		_, _, err = p.KubeConfig(context.Background(), request(legacy()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idp.refreshes.Load()).To(BeEquivalentTo(2))
+	})
+
+	ginkgo.It("should fail when the issuer rejects the refresh token", func() {
+		authInfo := legacy()
+		authInfo.AuthProvider.Config["refresh-token"] = "revoked"
+
+		_, _, err := p.KubeConfig(context.Background(), request(authInfo))
+		Expect(err).To(MatchError(ContainSubstring("invalid_grant")))
+	})
+
+	ginkgo.It("should not contact the issuer for kubeconfigs loaded from the cluster", func() {
+		req := request(legacy())
+		req.InCluster = true
+
+		_, _, err := p.KubeConfig(context.Background(), req)
+		Expect(err).To(MatchError(ContainSubstring("not supported for kubeconfigs loaded from a Secret or ConfigMap")))
+		Expect(idp.refreshes.Load()).To(BeZero())
+	})
+
+	ginkgo.It("should refuse a certificate authority outside the allowed directories", func() {
+		authInfo := legacy()
+		authInfo.AuthProvider.Config["idp-certificate-authority"] = "/etc/passwd"
+		req := request(authInfo)
+		req.ResolvePath = func(path string) (string, error) {
+			return "", errors.Errorf("%q is outside the allowed directories", path)
+		}
+
+		_, _, err := p.KubeConfig(context.Background(), req)
+		Expect(err).To(MatchError(ContainSubstring("outside the allowed directories")))
+		Expect(idp.refreshes.Load()).To(BeZero())
+	})
+
+	ginkgo.It("should read the refresh token from the kubelogin cache", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		cache := filepath.Join(dir, "cache", "oidc-login")
+		Expect(os.MkdirAll(cache, 0o700)).To(Succeed())
+
+		entry, err := json.Marshal(map[string]string{
+			"id_token":      idToken(idp.URL, "kubernetes", time.Now().Add(-time.Minute)),
+			"refresh_token": "refresh-1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.WriteFile(filepath.Join(cache, "0123abcd"), entry, 0o600)).To(Succeed())
+
+		authInfo := &api.AuthInfo{
+			LocationOfOrigin: filepath.Join(dir, "config"),
+			Exec: &api.ExecConfig{
+				Command: "kubectl",
+				Args: []string{
+					"oidc-login", "get-token",
+					"--oidc-issuer-url=" + idp.URL,
+					"--oidc-client-id", "kubernetes",
+					"--oidc-client-secret=secret",
+				},
+			},
+		}
+
+		details, _, err := p.KubeConfig(context.Background(), request(authInfo))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idp.refreshes.Load()).To(BeEquivalentTo(1))
+		Expect(details.AuthInfos["dev"].Token).NotTo(BeEmpty())
+		Expect(details.AuthInfos["dev"].Exec).To(BeNil())
+	})
+})
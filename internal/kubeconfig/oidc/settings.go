@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oidc
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// settings are the OIDC details read from a user in the kubeconfig.
+type settings struct {
+	issuerURL    string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	refreshToken string
+	idToken      string
+
+	// caData and caFile are the CA used to verify the issuer.
+	caData []byte
+	caFile string
+
+	// cacheDir is the kubelogin token cache directory.
+	cacheDir string
+}
+
+// isKubelogin returns true if the exec plugin is kubelogin requesting an
+// OIDC token, either run directly or as `kubectl oidc-login`. The Azure
+// kubelogin shares both the binary name and the get-token subcommand, so
+// only the issuer URL identifies OIDC.
+func isKubelogin(exec *api.ExecConfig) bool {
+	if exec == nil || !slices.Contains(exec.Args, "get-token") {
+		return false
+	}
+
+	return provider.ParseFlags(exec.Args)["oidc-issuer-url"] != ""
+}
+
+// isAuthProvider returns true for the legacy `auth-provider: oidc` block.
+func isAuthProvider(authProvider *api.AuthProviderConfig) bool {
+	return authProvider != nil && authProvider.Name == "oidc"
+}
+
+// settingsFromAuthProvider reads the legacy auth-provider configuration.
+func settingsFromAuthProvider(config map[string]string) (settings, error) {
+	s := settings{
+		issuerURL:    config["idp-issuer-url"],
+		clientID:     config["client-id"],
+		clientSecret: config["client-secret"],
+		refreshToken: config["refresh-token"],
+		idToken:      config["id-token"],
+		caFile:       config["idp-certificate-authority"],
+	}
+
+	if scopes := config["extra-scopes"]; scopes != "" {
+		s.scopes = strings.Split(scopes, ",")
+	}
+
+	if data := config["idp-certificate-authority-data"]; data != "" {
+		ca, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return s, errors.Wrap(err, "invalid idp-certificate-authority-data")
+		}
+		s.caData = ca
+	}
+
+	return s, nil
+}
+
+// settingsFromKubelogin reads the kubelogin flags from the exec args.
+func settingsFromKubelogin(exec *api.ExecConfig) (settings, error) {
+	flags := provider.ParseFlags(exec.Args)
+	s := settings{
+		issuerURL:    flags["oidc-issuer-url"],
+		clientID:     flags["oidc-client-id"],
+		clientSecret: flags["oidc-client-secret"],
+		caFile:       flags["certificate-authority"],
+		cacheDir:     flags["token-cache-dir"],
+	}
+
+	if scopes := flags["oidc-extra-scope"]; scopes != "" {
+		s.scopes = strings.Split(scopes, ",")
+	}
+
+	if data := flags["certificate-authority-data"]; data != "" {
+		ca, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return s, errors.Wrap(err, "invalid certificate-authority-data")
+		}
+		s.caData = ca
+	}
+
+	return s, nil
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package oidc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// claims are the fields of an ID token used by the provider.
+type claims struct {
+	Issuer   string   `json:"iss"`
+	Audience audience `json:"aud"`
+	Expiry   int64    `json:"exp"`
+}
+
+// audience is a JWT audience, which may be a string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+
+	return nil
+}
+
+// parseClaims decodes the claims of an ID token. The signature is not
+// verified as the token is only passed on to the API server, which does.
+func parseClaims(token string) (claims, error) {
+	var c claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return c, errors.New("id token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return c, errors.Wrap(err, "failed to decode id token")
+	}
+
+	if err = json.Unmarshal(payload, &c); err != nil {
+		return c, errors.Wrap(err, "failed to parse id token claims")
+	}
+
+	return c, nil
+}
+
+// expiry returns when the ID token expires.
+func (c claims) expiry() time.Time {
+	if c.Expiry == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(c.Expiry, 0)
+}
+
+// cachedToken is an entry in the kubelogin token cache.
+type cachedToken struct {
+	IDToken      string `json:"id_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// fromKubeloginCache finds the tokens kubelogin cached for the issuer and
+// client. kubelogin names cache files by a hash of its settings, which
+// differs between versions, so every file is read and matched on the
+// issuer and audience of its ID token instead.
+func fromKubeloginCache(dir, issuer, clientID string) (cachedToken, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return cachedToken{}, errors.Wrap(err, "failed to read kubelogin token cache")
+	}
+
+	var (
+		found  cachedToken
+		latest time.Time
+	)
+	for _, entry := range entries {
+		// Symlinks are not followed so that the cache cannot be used to
+		// read files elsewhere.
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var token cachedToken
+		if json.Unmarshal(content, &token) != nil || token.RefreshToken == "" {
+			continue
+		}
+
+		c, err := parseClaims(token.IDToken)
+		if err != nil || strings.TrimSuffix(c.Issuer, "/") != strings.TrimSuffix(issuer, "/") ||
+			!slices.Contains(c.Audience, clientID) {
+			continue
+		}
+
+		if c.expiry().After(latest) || found.RefreshToken == "" {
+			found, latest = token, c.expiry()
+		}
+	}
+
+	if found.RefreshToken == "" {
+		return found, errors.Errorf("no refresh token for %s found in kubelogin token cache %s", issuer, dir)
+	}
+
+	return found, nil
+}
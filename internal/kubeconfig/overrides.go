@@ -0,0 +1,229 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9]+")
+
+// target describes where and how a context is exported.
+type target struct {
+	// context is the name of the context in the source kubeconfig.
+	context string
+
+	// name is the name of the context in the exported kubeconfig.
+	name string
+
+	namespace   string
+	secretName  string
+	labels      map[string]string
+	annotations map[string]string
+	format      kccnv1alpha1.SecretFormat
+
+	// adopt is set when the context uses the default namespace and secret
+	// name, under which secrets were created before they carried the
+	// ownership labels.
+	adopt bool
+}
+
+// overrideFor returns the override for a context from the Cluster spec,
+// or an empty override if there is none.
+func (m *Manager) overrideFor(ctx Context) kccnv1alpha1.ContextOverride {
+	for _, o := range m.cluster.Spec.Overrides {
+		if o.Context == ctx.name {
+			return o
+		}
+	}
+
+	return kccnv1alpha1.ContextOverride{}
+}
+
+// targetFor works out the names, labels and annotations used to export a
+// context, applying any override from the Cluster spec.
+func (m *Manager) targetFor(ctx Context) target {
+	t := target{
+		context: ctx.name,
+		name:    ctx.name,
+	}
+
+	override := m.overrideFor(ctx)
+	if override.Alias != "" {
+		t.name = override.Alias
+	}
+
+	name := sanitizeName(t.name)
+
+	t.namespace = name
+	if override.Namespace != "" {
+		t.namespace = override.Namespace
+	} else if prefix := m.cluster.Spec.NamespacePrefix; prefix != "" {
+		t.namespace = strings.TrimSuffix(prefix, "-") + "-" + name
+	}
+
+	t.secretName = name + "-kubeconfig"
+	if override.SecretName != "" {
+		t.secretName = override.SecretName
+	}
+	t.adopt = override.Namespace == "" && override.SecretName == ""
+
+	t.labels = map[string]string{}
+	for k, v := range override.Labels {
+		t.labels[k] = v
+	}
+	// Ownership labels always win over user supplied labels.
+	for k, v := range m.ownerLabels() {
+		t.labels[k] = v
+	}
+
+	t.annotations = map[string]string{}
+	for k, v := range override.Annotations {
+		t.annotations[k] = v
+	}
+	t.annotations[AnnotationContext] = ctx.name
+	t.format = m.secretFormatFor(override)
+
+	return t
+}
+
+// validate checks that the context can be exported to the target.
+func (t target) validate() error {
+	// Cluster API finds the kubeconfig secret by the cluster name alone
+	if t.format.ClusterAPI != nil &&
+		(!strings.HasSuffix(t.secretName, "-kubeconfig") || clusterAPIName(t) == "") {
+		return errors.Errorf("secret name %q must be <cluster>-kubeconfig to be used with cluster api", t.secretName)
+	}
+
+	return validateSecretFormat(t.format)
+}
+
+// sanitizeName converts a context name into a string that is safe to use
+// in a namespace or secret name, e.g.
+// `arn:aws:eks:us-east-1:000000000000:cluster/eks1` becomes
+// `arn-aws-eks-us-east-1-000000000000-cluster-eks1`.
+func sanitizeName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// appliedMetadata is the content of AnnotationAppliedMetadata.
+type appliedMetadata struct {
+	Labels      []string `json:"labels,omitempty"`
+	Annotations []string `json:"annotations,omitempty"`
+}
+
+// syncMetadata sets labels and annotations on obj and removes any keys the
+// operator applied previously which are no longer wanted, such as a label
+// removed from an override. Keys set by anyone else are left alone. The
+// keys applied are recorded in AnnotationAppliedMetadata. It reports
+// whether anything changed.
+func syncMetadata(obj metav1.Object, labels, annotations map[string]string) bool {
+	var previous appliedMetadata
+	if record, ok := obj.GetAnnotations()[AnnotationAppliedMetadata]; ok {
+		_ = json.Unmarshal([]byte(record), &previous)
+	}
+
+	record, _ := json.Marshal(appliedMetadata{
+		Labels:      sortedKeys(labels),
+		Annotations: sortedKeys(annotations),
+	})
+	desired := map[string]string{AnnotationAppliedMetadata: string(record)}
+	for k, v := range annotations {
+		desired[k] = v
+	}
+
+	current, labelsChanged := mergeMetadata(obj.GetLabels(), labels, previous.Labels)
+	obj.SetLabels(current)
+	current, annotationsChanged := mergeMetadata(obj.GetAnnotations(), desired, previous.Annotations)
+	obj.SetAnnotations(current)
+
+	return labelsChanged || annotationsChanged
+}
+
+// mergeMetadata copies desired into current and removes the keys in
+// previous that are not desired, reporting whether anything changed.
+func mergeMetadata(current, desired map[string]string, previous []string) (map[string]string, bool) {
+	if current == nil {
+		current = map[string]string{}
+	}
+
+	var changed bool
+	for _, k := range previous {
+		if _, ok := desired[k]; ok {
+			continue
+		}
+		if _, ok := current[k]; ok {
+			delete(current, k)
+			changed = true
+		}
+	}
+
+	for k, v := range desired {
+		if existing, ok := current[k]; !ok || existing != v {
+			current[k] = v
+			changed = true
+		}
+	}
+
+	return current, changed
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// renameContext returns a copy of the current context of cfg in which the
+// context and cluster are called name.
+func renameContext(cfg *api.Config, name string) *api.Config {
+	ctx, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return cfg
+	}
+
+	renamed := api.NewConfig()
+	renamed.APIVersion = cfg.APIVersion
+	renamed.CurrentContext = name
+	renamed.Contexts[name] = &api.Context{
+		Cluster:  name,
+		AuthInfo: ctx.AuthInfo,
+	}
+
+	if cluster, ok := cfg.Clusters[ctx.Cluster]; ok {
+		renamed.Clusters[name] = cluster
+	}
+
+	if authInfo, ok := cfg.AuthInfos[ctx.AuthInfo]; ok {
+		renamed.AuthInfos[ctx.AuthInfo] = authInfo
+	}
+
+	return renamed
+}
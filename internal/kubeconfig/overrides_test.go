@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("targetFor", func() {
+	arn := "arn:aws:eks:us-east-1:000000000000:cluster/eks1"
+
+	newManager := func(overrides ...kccnv1alpha1.ContextOverride) *Manager {
+		m := newTestManager()
+		m.cluster.Spec.Overrides = overrides
+		return m
+	}
+
+	ginkgo.It("should derive names from the context", func() {
+		t := newManager().targetFor(Context{name: arn})
+		Expect(t.name).To(Equal(arn))
+		Expect(t.namespace).To(Equal("cluster-arn-aws-eks-us-east-1-000000000000-cluster-eks1"))
+		Expect(t.secretName).To(Equal("arn-aws-eks-us-east-1-000000000000-cluster-eks1-kubeconfig"))
+		Expect(t.annotations).To(HaveKeyWithValue(AnnotationContext, arn))
+		Expect(t.adopt).To(BeTrue())
+	})
+
+	ginkgo.It("should derive names from the alias", func() {
+		t := newManager(kccnv1alpha1.ContextOverride{Context: arn, Alias: "eks1"}).
+			targetFor(Context{name: arn})
+		Expect(t.name).To(Equal("eks1"))
+		Expect(t.namespace).To(Equal("cluster-eks1"))
+		Expect(t.secretName).To(Equal("eks1-kubeconfig"))
+		Expect(t.annotations).To(HaveKeyWithValue(AnnotationContext, arn))
+	})
+
+	ginkgo.It("should use explicit names, labels and annotations", func() {
+		t := newManager(kccnv1alpha1.ContextOverride{
+			Context:     "kind-tenant1",
+			Namespace:   "tenant1",
+			SecretName:  "tenant1",
+			Labels:      map[string]string{"team": "a", LabelClusterName: "other"},
+			Annotations: map[string]string{"note": "x"},
+		}).targetFor(Context{name: "kind-tenant1"})
+		Expect(t.namespace).To(Equal("tenant1"))
+		Expect(t.secretName).To(Equal("tenant1"))
+		Expect(t.adopt).To(BeFalse())
+		Expect(t.labels).To(HaveKeyWithValue("team", "a"))
+		Expect(t.labels).To(HaveKeyWithValue(LabelClusterName, "sample"))
+		Expect(t.annotations).To(HaveKeyWithValue("note", "x"))
+	})
+})
+
+var _ = ginkgo.Describe("syncMetadata", func() {
+	ginkgo.It("should remove keys dropped from an override", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"external": "kept"},
+		}}
+
+		Expect(syncMetadata(secret,
+			map[string]string{"team": "a", "tier": "dev"},
+			map[string]string{"note": "x"})).To(BeTrue())
+		Expect(secret.Labels).To(Equal(map[string]string{"external": "kept", "team": "a", "tier": "dev"}))
+		Expect(secret.Annotations).To(HaveKeyWithValue("note", "x"))
+
+		Expect(syncMetadata(secret, map[string]string{"team": "b"}, map[string]string{})).To(BeTrue())
+		Expect(secret.Labels).To(Equal(map[string]string{"external": "kept", "team": "b"}))
+		Expect(secret.Annotations).NotTo(HaveKey("note"))
+
+		Expect(syncMetadata(secret, map[string]string{"team": "b"}, map[string]string{})).To(BeFalse())
+	})
+})
+
+var _ = ginkgo.Describe("renameContext", func() {
+	ginkgo.It("should rename the context and cluster", func() {
+		cfg := api.NewConfig()
+		cfg.CurrentContext = "source"
+		cfg.Contexts["source"] = &api.Context{Cluster: "source-cluster", AuthInfo: "user"}
+		cfg.Clusters["source-cluster"] = &api.Cluster{Server: "https://192.168.1.2:6443"}
+		cfg.AuthInfos["user"] = &api.AuthInfo{Token: "token"}
+
+		renamed := renameContext(cfg, "alias")
+		Expect(renamed.CurrentContext).To(Equal("alias"))
+		Expect(renamed.Contexts).To(HaveKeyWithValue("alias", &api.Context{Cluster: "alias", AuthInfo: "user"}))
+		Expect(renamed.Clusters["alias"].Server).To(Equal("https://192.168.1.2:6443"))
+		Expect(renamed.AuthInfos).To(HaveKey("user"))
+	})
+})
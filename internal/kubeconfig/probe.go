@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// probeTimeout bounds each request made while checking a cluster.
+const probeTimeout = 10 * time.Second
+
+// probeResult is the outcome of checking that a cluster is reachable.
+type probeResult struct {
+	category string
+	err      error
+	latency  time.Duration
+	version  *version.Info
+	facts    *kccnv1alpha1.ClusterFacts
+}
+
+// status converts the result into its API representation.
+func (r probeResult) status() *kccnv1alpha1.ProbeResult {
+	result := &kccnv1alpha1.ProbeResult{
+		Category: r.category,
+		Latency:  metav1.Duration{Duration: r.latency},
+	}
+	if r.err != nil {
+		result.Error = r.err.Error()
+	}
+	if r.version != nil {
+		result.ServerVersion = r.version.GitVersion
+	}
+
+	return result
+}
+
+// reason returns the status reason and message for a failed probe.
+func (r probeResult) reason() (string, string) {
+	var probeErr *probeError
+	switch {
+	case errors.As(r.err, &probeErr):
+		return probeErr.reason, probeErr.err.Error()
+	case r.category == kccnv1alpha1.ProbeCategoryUnauthorized:
+		return kccnv1alpha1.ReasonUnauthorized, r.err.Error()
+	case r.category == kccnv1alpha1.ProbeCategoryForbidden:
+		return kccnv1alpha1.ReasonForbidden, r.err.Error()
+	}
+
+	return kccnv1alpha1.ReasonUnreachable, r.err.Error()
+}
+
+// isNetworkFailure returns true for the categories of failure that a
+// firewall rule may fix.
+func isNetworkFailure(category string) bool {
+	switch category {
+	case kccnv1alpha1.ProbeCategoryConnectionRefused,
+		kccnv1alpha1.ProbeCategoryNetworkUnreachable,
+		kccnv1alpha1.ProbeCategoryTimeout:
+		return true
+	}
+
+	return false
+}
+
+// probeCluster checks that the cluster can be reached using the kubeconfig
+// stored under key in the secret. The stored kubeconfig carries any
+// tls-server-name so the certificate is verified exactly as a consumer of
+// the secret would.
+func (m *Manager) probeCluster(namespace, secretName, key string) probeResult {
+	secret := &corev1.Secret{}
+	if err := m.client.Get(m.context, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
+		return probeResult{category: kccnv1alpha1.ProbeCategoryUnknown, err: errors.Wrap(err, "failed to get secret")}
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[key])
+	if err != nil {
+		return probeResult{
+			category: kccnv1alpha1.ProbeCategoryUnknown,
+			err:      errors.Wrap(err, "failed to build config from kubeconfig"),
+		}
+	}
+
+	// The operator cannot run exec plugins passed through to the secret,
+	// so only the reachability and certificate of the cluster are checked
+	// for those contexts.
+	config.ExecProvider = nil
+
+	result := probe(m.context, config)
+	if result.err != nil {
+		m.log.Info("cluster not reachable", "secret", secretName, "category", result.category,
+			"error", result.err.Error())
+	}
+
+	// Inspect the server certificate so that a SAN or CA problem can be
+	// reported instead of a generic TLS error.
+	if result.category == kccnv1alpha1.ProbeCategoryTLS {
+		if diagnosis := diagnoseTLS(config, m.cluster.Spec.RemapToIp); diagnosis != nil {
+			result.err = diagnosis
+		}
+	}
+
+	return result
+}
+
+// probe fetches /version from the server, then lists namespaces to check
+// that the credentials are accepted. Facts about the cluster are collected
+// once it is known to be reachable.
+func probe(ctx context.Context, config *rest.Config) probeResult {
+	config = rest.CopyConfig(config)
+	config.Timeout = probeTimeout
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return probeResult{category: kccnv1alpha1.ProbeCategoryUnknown, err: errors.Wrap(err, "failed to create client")}
+	}
+
+	start := time.Now()
+	info, err := discoveryClient.ServerVersion()
+	result := probeResult{latency: time.Since(start), version: info}
+	if err != nil {
+		result.category = classify(err)
+		result.err = errors.Wrap(err, "failed to get server version")
+		return result
+	}
+
+	c, err := client.New(config, client.Options{})
+	if err != nil {
+		result.category = classify(err)
+		result.err = errors.Wrap(err, "failed to create client")
+		return result
+	}
+
+	if err = c.List(ctx, &corev1.NamespaceList{}, client.Limit(1)); err != nil {
+		result.category = classify(err)
+		result.err = errors.Wrap(err, "failed to list namespaces")
+		return result
+	}
+
+	result.category = kccnv1alpha1.ProbeCategoryNone
+	result.facts = collectFacts(ctx, c, discoveryClient, info)
+	return result
+}
+
+// classify returns the probe category for an error returned by the API
+// server or the transport.
+func classify(err error) string {
+	var (
+		dnsErr       *net.DNSError
+		netErr       net.Error
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		verifyErr    *tls.CertificateVerificationError
+	)
+
+	switch {
+	case apierrors.IsUnauthorized(err):
+		return kccnv1alpha1.ProbeCategoryUnauthorized
+	case apierrors.IsForbidden(err):
+		return kccnv1alpha1.ProbeCategoryForbidden
+	case errors.As(err, &dnsErr):
+		return kccnv1alpha1.ProbeCategoryDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return kccnv1alpha1.ProbeCategoryConnectionRefused
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return kccnv1alpha1.ProbeCategoryNetworkUnreachable
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidErr), errors.As(err, &verifyErr):
+		return kccnv1alpha1.ProbeCategoryTLS
+	case errors.Is(err, context.DeadlineExceeded), apierrors.IsTimeout(err),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return kccnv1alpha1.ProbeCategoryTimeout
+	}
+
+	return kccnv1alpha1.ProbeCategoryUnknown
+}
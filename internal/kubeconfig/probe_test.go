@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// apiServer is a minimal stand in for an API server that serves /version,
+// discovery for namespaces and nodes, and responds to namespace lists with
+// status.
+func apiServer(status int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/version":
+			_, _ = fmt.Fprint(w, `{"gitVersion":"v1.31.0","platform":"linux/amd64"}`)
+		case "/api":
+			_, _ = fmt.Fprint(w, `{"kind":"APIVersions","versions":["v1"]}`)
+		case "/apis":
+			_, _ = fmt.Fprint(w, `{"kind":"APIGroupList","groups":[`+
+				`{"name":"cert-manager.io","versions":[{"groupVersion":"cert-manager.io/v1","version":"v1"}]},`+
+				`{"name":"example.com","versions":[{"groupVersion":"example.com/v1","version":"v1"}]}]}`)
+		case "/api/v1":
+			_, _ = fmt.Fprint(w, `{"kind":"APIResourceList","groupVersion":"v1","resources":[`+
+				`{"name":"namespaces","namespaced":false,"kind":"Namespace","verbs":["list"]},`+
+				`{"name":"nodes","namespaced":false,"kind":"Node","verbs":["list"]}]}`)
+		case "/api/v1/nodes":
+			// Nodes are served a page at a time
+			if r.URL.Query().Get("limit") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.URL.Query().Get("continue") == "" {
+				_, _ = fmt.Fprint(w, `{"kind":"NodeList","apiVersion":"v1","metadata":{"continue":"b"},"items":[`+
+					`{"metadata":{"name":"a"},"status":{"conditions":[{"type":"Ready","status":"True"}]}}]}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"kind":"NodeList","apiVersion":"v1","items":[`+
+				`{"metadata":{"name":"b"},"status":{"conditions":[{"type":"Ready","status":"False"}]}}]}`)
+		case "/api/v1/namespaces":
+			w.WriteHeader(status)
+			if status == http.StatusOK {
+				_, _ = fmt.Fprint(w, `{"kind":"NamespaceList","apiVersion":"v1","items":[]}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","code":%d,"reason":%q}`,
+				status, http.StatusText(status))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+var _ = ginkgo.Describe("Reachability probe", func() {
+	ginkgo.It("should report the server version when the cluster is reachable", func() {
+		server := apiServer(http.StatusOK)
+		defer server.Close()
+
+		result := probe(context.Background(), &rest.Config{Host: server.URL})
+		Expect(result.err).NotTo(HaveOccurred())
+		Expect(result.category).To(Equal(kccnv1alpha1.ProbeCategoryNone))
+		Expect(result.status().ServerVersion).To(Equal("v1.31.0"))
+		Expect(result.latency).To(BeNumerically(">", 0))
+	})
+
+	ginkgo.It("should collect facts from a reachable cluster", func() {
+		server := apiServer(http.StatusOK)
+		defer server.Close()
+
+		result := probe(context.Background(), &rest.Config{Host: server.URL})
+		Expect(result.err).NotTo(HaveOccurred())
+		Expect(result.facts).NotTo(BeNil())
+		Expect(result.facts.KubernetesVersion).To(Equal("v1.31.0"))
+		Expect(result.facts.Platform).To(Equal("linux/amd64"))
+		Expect(*result.facts.Nodes).To(BeEquivalentTo(2))
+		Expect(*result.facts.ReadyNodes).To(BeEquivalentTo(1))
+		Expect(result.facts.APIGroups).To(Equal([]string{"cert-manager.io"}))
+	})
+
+	ginkgo.It("should classify rejected credentials", func() {
+		server := apiServer(http.StatusForbidden)
+		defer server.Close()
+
+		result := probe(context.Background(), &rest.Config{Host: server.URL})
+		Expect(result.category).To(Equal(kccnv1alpha1.ProbeCategoryForbidden))
+		reason, _ := result.reason()
+		Expect(reason).To(Equal(kccnv1alpha1.ReasonForbidden))
+		Expect(isNetworkFailure(result.category)).To(BeFalse())
+	})
+
+	ginkgo.It("should classify a refused connection as a network failure", func() {
+		result := probe(context.Background(), &rest.Config{Host: "http://127.0.0.1:1"})
+		Expect(result.category).To(Equal(kccnv1alpha1.ProbeCategoryConnectionRefused))
+		reason, _ := result.reason()
+		Expect(reason).To(Equal(kccnv1alpha1.ReasonUnreachable))
+		Expect(isNetworkFailure(result.category)).To(BeTrue())
+	})
+
+	ginkgo.DescribeTable("classify",
+		func(err error, category string) {
+			Expect(classify(err)).To(Equal(category))
+		},
+		ginkgo.Entry("unauthorized", apierrors.NewUnauthorized("no"), kccnv1alpha1.ProbeCategoryUnauthorized),
+		ginkgo.Entry("forbidden",
+			apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", errors.New("no")),
+			kccnv1alpha1.ProbeCategoryForbidden),
+		ginkgo.Entry("dns", &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host"}},
+			kccnv1alpha1.ProbeCategoryDNS),
+		ginkgo.Entry("unreachable", &url.Error{Op: "Get", Err: &net.OpError{
+			Op: "dial", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH),
+		}}, kccnv1alpha1.ProbeCategoryNetworkUnreachable),
+		ginkgo.Entry("timeout", errors.Wrap(context.DeadlineExceeded, "get"), kccnv1alpha1.ProbeCategoryTimeout),
+		ginkgo.Entry("tls", &url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}, kccnv1alpha1.ProbeCategoryTLS),
+		ginkgo.Entry("unknown", errors.New("boom"), kccnv1alpha1.ProbeCategoryUnknown),
+	)
+})
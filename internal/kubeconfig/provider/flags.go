@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import "strings"
+
+// ParseFlags returns the flags in the args of an exec plugin, accepting
+// `-f value`, `--flag value` and `--flag=value`. Flags without a value are
+// recorded as empty and repeated flags are joined with a comma.
+func ParseFlags(args []string) map[string]string {
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !ok && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			value = args[i+1]
+			i++
+		}
+
+		if existing := flags[name]; existing != "" && value != "" {
+			value = existing + "," + value
+		}
+		flags[name] = value
+	}
+
+	return flags
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("ParseFlags", func() {
+	ginkgo.It("should read short, long and assigned flags", func() {
+		Expect(ParseFlags([]string{
+			"eks", "get-token", "--cluster-name", "eks1", "--region=eu-west-1", "-i", "eks2", "--verbose",
+		})).To(Equal(map[string]string{
+			"cluster-name": "eks1",
+			"region":       "eu-west-1",
+			"i":            "eks2",
+			"verbose":      "",
+		}))
+	})
+
+	ginkgo.It("should join repeated flags", func() {
+		Expect(ParseFlags([]string{
+			"oidc-login", "get-token", "--oidc-extra-scope=email", "--oidc-extra-scope", "groups",
+		})).To(HaveKeyWithValue("oidc-extra-scope", "email,groups"))
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Request holds everything a Provider needs to export a context.
+type Request struct {
+	// Context is the name of the context in the source kubeconfig.
+	Context string
+
+	// Cluster is the name of the cluster the context refers to.
+	Cluster string
+
+	// User is the name of the user the context refers to.
+	User string
+
+	// AuthInfo is the user the context refers to, as written in the
+	// source kubeconfig.
+	AuthInfo *api.AuthInfo
+
+	// Config is the client config for the context, with the server
+	// remapped and any file referenced certificates inlined.
+	Config *rest.Config
+
+	// ResolvePath returns where a file referenced by the user can be read,
+	// resolving relative paths against the kubeconfig and applying any
+	// host path mappings. It fails for files the user may not read.
+	ResolvePath func(path string) (string, error)
+
+	// InCluster is set when the kubeconfig was loaded from a Secret or
+	// ConfigMap. Whoever wrote it need not be trusted, so providers must
+	// not read files or contact endpoints that it names.
+	InCluster bool
+}
+
+// Provider exports the credentials for one kind of authentication.
+type Provider interface {
+	// Name is the name used to select the provider for a context.
+	Name() string
+
+	// Detect returns true if the provider handles the user.
+	Detect(authInfo *api.AuthInfo) bool
+
+	// KubeConfig builds the exported kubeconfig for a context. If the
+	// credentials are short lived, the time at which they expire is
+	// returned so that they can be refreshed in time. A zero time means
+	// the credentials do not expire.
+	KubeConfig(ctx context.Context, req Request) (cfg *api.Config, expiry time.Time, err error)
+}
+
+// Registry holds the known providers in the order they are tried when
+// detecting the provider for a context.
+type Registry struct {
+	mu        sync.RWMutex
+	providers []Provider
+}
+
+// NewRegistry creates a Registry with the given providers.
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{}
+	for _, p := range providers {
+		r.Register(p)
+	}
+
+	return r
+}
+
+// Register adds a provider, replacing any provider with the same name.
+// New providers are tried after those already registered.
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.providers {
+		if existing.Name() == p.Name() {
+			r.providers[i] = p
+			return
+		}
+	}
+	r.providers = append(r.providers, p)
+}
+
+// Get returns the provider registered under name.
+func (r *Registry) Get(name string) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// Detect returns the first provider that handles the user.
+func (r *Registry) Detect(authInfo *api.AuthInfo) (Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.providers {
+		if p.Detect(authInfo) {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// Names returns the names of the registered providers.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.providers))
+	for _, p := range r.providers {
+		names = append(names, p.Name())
+	}
+
+	return names
+}
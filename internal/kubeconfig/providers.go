@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/aws"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/clientcert"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/exec"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/oidc"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/token"
+)
+
+// Providers are the credential providers available to export contexts,
+// in the order they are tried when detecting the provider for a context.
+var Providers = provider.NewRegistry(
+	aws.Provider{},
+	oidc.NewProvider(CredentialRefreshWindow),
+	token.Provider{},
+	clientcert.Provider{},
+	exec.Provider{},
+)
+
+// providerFor returns the provider for a context, either the one named
+// in its override or the first provider that detects its user.
+func (m *Manager) providerFor(ctx Context, config *kconfig) (provider.Provider, error) {
+	if name := m.overrideFor(ctx).Provider; name != "" {
+		p, ok := Providers.Get(name)
+		if !ok {
+			return nil, errors.Errorf("provider %q is not supported, expected one of %v", name, Providers.Names())
+		}
+		return p, nil
+	}
+
+	p, ok := Providers.Detect(config.authInfo)
+	if !ok {
+		return nil, errors.Errorf("no provider supports the authentication used by user %q", ctx.user)
+	}
+
+	return p, nil
+}
+
+// execPlugin returns the command of the exec plugin passed through to the
+// exported kubeconfig, or an empty string if the credentials were minted
+// by the operator.
+func execPlugin(details *api.Config) string {
+	ctx, ok := details.Contexts[details.CurrentContext]
+	if !ok {
+		return ""
+	}
+
+	authInfo, ok := details.AuthInfos[ctx.AuthInfo]
+	if !ok || authInfo.Exec == nil {
+		return ""
+	}
+
+	return authInfo.Exec.Command
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/aws"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/clientcert"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/exec"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/token"
+)
+
+var _ = ginkgo.Describe("Providers", func() {
+	var m *Manager
+
+	ginkgo.BeforeEach(func() {
+		m = newTestManager()
+	})
+
+	providerName := func(authInfo *api.AuthInfo) string {
+		p, err := m.providerFor(Context{name: "ctx", user: "user"}, &kconfig{authInfo: authInfo})
+		Expect(err).NotTo(HaveOccurred())
+		return p.Name()
+	}
+
+	ginkgo.It("should detect client certificates", func() {
+		Expect(providerName(&api.AuthInfo{ClientCertificateData: []byte("cert")})).To(Equal(clientcert.Name))
+		Expect(providerName(&api.AuthInfo{ClientCertificate: "/tmp/client.crt"})).To(Equal(clientcert.Name))
+	})
+
+	ginkgo.It("should detect tokens and basic auth", func() {
+		Expect(providerName(&api.AuthInfo{Token: "abc"})).To(Equal(token.Name))
+		Expect(providerName(&api.AuthInfo{TokenFile: "/tmp/token"})).To(Equal(token.Name))
+		Expect(providerName(&api.AuthInfo{Username: "admin", Password: "secret"})).To(Equal(token.Name))
+		Expect(providerName(&api.AuthInfo{
+			Token: "abc", ClientCertificateData: []byte("cert"),
+		})).To(Equal(token.Name))
+	})
+
+	ginkgo.It("should export a token read from a token file", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "token"), []byte("abc\n"), 0o600)).To(Succeed())
+
+		config := api.NewConfig()
+		config.Contexts["k3s"] = &api.Context{Cluster: "k3s", AuthInfo: "k3s-admin"}
+		config.Clusters["k3s"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["k3s-admin"] = &api.AuthInfo{
+			TokenFile:        "token",
+			LocationOfOrigin: filepath.Join(dir, "config"),
+		}
+
+		c, err := ClientConfig("k3s", "192.168.1.2", GetContextsOptions{config: config})
+		Expect(err).NotTo(HaveOccurred())
+
+		p, err := m.providerFor(Context{name: "k3s", user: "k3s-admin"}, c)
+		Expect(err).NotTo(HaveOccurred())
+		details, expiry, err := p.KubeConfig(context.Background(), provider.Request{
+			Context:  "k3s",
+			Cluster:  "k3s",
+			User:     "k3s-admin",
+			AuthInfo: c.authInfo,
+			Config:   c.Config,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry.IsZero()).To(BeTrue())
+		Expect(details.AuthInfos["k3s-admin"].Token).To(Equal("abc"))
+		Expect(details.AuthInfos["k3s-admin"].TokenFile).To(BeEmpty())
+		Expect(details.Clusters["k3s"].Server).To(Equal("https://192.168.1.2:6443"))
+	})
+
+	ginkgo.It("should not export the operator service account token", func() {
+		config := api.NewConfig()
+		config.Contexts["k3s"] = &api.Context{Cluster: "k3s", AuthInfo: "k3s-admin"}
+		config.Clusters["k3s"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["k3s-admin"] = &api.AuthInfo{
+			TokenFile:        "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			LocationOfOrigin: filepath.Join(ginkgo.GinkgoT().TempDir(), "config"),
+		}
+
+		_, err := ClientConfig("k3s", "192.168.1.2", GetContextsOptions{config: config})
+		Expect(err).To(MatchError(ContainSubstring("cannot read tokenFile")))
+		Expect(err).To(MatchError(ContainSubstring("outside the kubeconfig directory")))
+	})
+
+	ginkgo.It("should refuse token files in kubeconfigs loaded from the cluster", func() {
+		dir := ginkgo.GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "token"), []byte("abc\n"), 0o600)).To(Succeed())
+
+		config := api.NewConfig()
+		config.Contexts["k3s"] = &api.Context{Cluster: "k3s", AuthInfo: "k3s-admin"}
+		config.Clusters["k3s"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["k3s-admin"] = &api.AuthInfo{TokenFile: filepath.Join(dir, "token")}
+
+		_, err := ClientConfig("k3s", "192.168.1.2", GetContextsOptions{config: config, inCluster: true})
+		Expect(err).To(MatchError(ContainSubstring("loaded from a Secret or ConfigMap")))
+	})
+
+	ginkgo.It("should detect eks exec plugins", func() {
+		Expect(providerName(&api.AuthInfo{Exec: &api.ExecConfig{Command: "aws"}})).To(Equal(aws.Name))
+		Expect(providerName(&api.AuthInfo{
+			Exec: &api.ExecConfig{Command: "/usr/local/bin/aws-iam-authenticator"},
+		})).To(Equal(aws.Name))
+	})
+
+	ginkgo.It("should pass through other exec plugins", func() {
+		authInfo := &api.AuthInfo{Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "gke-gcloud-auth-plugin",
+			Env:        []api.ExecEnvVar{{Name: "CLOUDSDK_CORE_PROJECT", Value: "dev"}},
+		}}
+		Expect(providerName(authInfo)).To(Equal(exec.Name))
+
+		p, err := m.providerFor(Context{name: "gke", user: "gke-user"}, &kconfig{authInfo: authInfo})
+		Expect(err).NotTo(HaveOccurred())
+		details, _, err := p.KubeConfig(context.Background(), provider.Request{
+			Context:  "gke",
+			Cluster:  "gke",
+			User:     "gke-user",
+			AuthInfo: authInfo,
+			Config:   &rest.Config{Host: "https://192.168.1.2:6443"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(execPlugin(details)).To(Equal("gke-gcloud-auth-plugin"))
+	})
+
+	ginkgo.It("should pass through the azure kubelogin plugin", func() {
+		Expect(providerName(&api.AuthInfo{Exec: &api.ExecConfig{
+			Command: "kubelogin",
+			Args: []string{
+				"get-token",
+				"--server-id", "6dae42f8-4368-4678-94ff-3960e28e3630",
+				"--login", "azurecli",
+			},
+		}})).To(Equal(exec.Name))
+	})
+
+	ginkgo.It("should fail when no provider detects the user", func() {
+		_, err := m.providerFor(Context{name: "ctx", user: "user"},
+			&kconfig{authInfo: &api.AuthInfo{AuthProvider: &api.AuthProviderConfig{Name: "gcp"}}})
+		Expect(err).To(MatchError(ContainSubstring(`user "user"`)))
+	})
+
+	ginkgo.It("should use the provider selected in the override", func() {
+		m.cluster.Spec.Overrides = []kccnv1alpha1.ContextOverride{{Context: "ctx", Provider: aws.Name}}
+		Expect(providerName(&api.AuthInfo{ClientCertificateData: []byte("cert")})).To(Equal(aws.Name))
+	})
+
+	ginkgo.It("should reject an unknown provider in the override", func() {
+		m.cluster.Spec.Overrides = []kccnv1alpha1.ContextOverride{{Context: "ctx", Provider: "azure"}}
+		_, err := m.providerFor(Context{name: "ctx"}, &kconfig{authInfo: &api.AuthInfo{}})
+		Expect(err).To(MatchError(ContainSubstring(`provider "azure" is not supported`)))
+		Expect(err).To(MatchError(ContainSubstring("client-cert")))
+	})
+
+	ginkgo.It("should report an unknown provider on the context", func() {
+		config := api.NewConfig()
+		config.Contexts["ctx"] = &api.Context{Cluster: "ctx", AuthInfo: "user"}
+		config.Clusters["ctx"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["user"] = &api.AuthInfo{Token: "token"}
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "config")
+		Expect(clientcmd.WriteToFile(*config, path)).To(Succeed())
+
+		m.cluster.Spec.KubeConfigPath = path
+		m.cluster.Spec.Overrides = []kccnv1alpha1.ContextOverride{{Context: "ctx", Provider: "azure"}}
+		ginkgo.DeferCleanup(m.deleteMetrics)
+
+		status, err := m.ReconcileKubeconfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.ClusterStatus).To(HaveKeyWithValue("ctx", And(
+			HaveField("Ready", BeFalse()),
+			HaveField("Reason", kccnv1alpha1.ReasonUnsupportedProvider),
+			HaveField("Message", ContainSubstring(`provider "azure" is not supported`)),
+		)))
+	})
+})
@@ -0,0 +1,226 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// maxPrunedHistory is the number of pruned resources kept in status.
+const maxPrunedHistory = 20
+
+// prune removes the namespaces and secrets owned by the Cluster whose
+// context is no longer present in the kubeconfig, or which are no longer
+// where their context is exported to, e.g. after the namespace or secret
+// name in an override was changed. status.ClusterAPIClusters is cleared
+// once no placeholder Cluster API Clusters are left.
+func (m *Manager) prune(status *Status, contexts ContextList) ([]kccnv1alpha1.PrunedResource, error) {
+	pruned := []kccnv1alpha1.PrunedResource{}
+	if m.cluster.Spec.Prune != nil && !*m.cluster.Spec.Prune {
+		return pruned, nil
+	}
+
+	now := metav1.Now()
+	current := m.currentObjects(contexts)
+
+	// Secrets are pruned first as they may live in a namespace that the
+	// operator did not create.
+	secrets := &corev1.SecretList{}
+	if err := m.client.List(m.context, secrets, client.MatchingLabels(m.ownerLabels())); err != nil {
+		return pruned, errors.Wrap(err, "failed to list secrets")
+	}
+
+	// Overrides may place several secrets in the same namespace. Keep
+	// track of namespaces still holding a secret for a live context.
+	inUse := map[string]bool{}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		deleted, err := m.pruneObject(secret, current[objectKey("Secret", secret)], contexts, now.Time)
+		if err != nil {
+			return pruned, errors.Wrap(err, "failed to prune secret "+secret.Name)
+		}
+		if !deleted {
+			inUse[secret.Namespace] = true
+			continue
+		}
+
+		pruned = append(pruned, kccnv1alpha1.PrunedResource{
+			Kind:      "Secret",
+			Name:      secret.Name,
+			Namespace: secret.Namespace,
+			Context:   secret.Annotations[AnnotationContext],
+			PrunedAt:  now,
+		})
+	}
+
+	clusters, err := m.ownedClusterAPIClusters()
+	if err != nil {
+		return pruned, err
+	}
+
+	remaining := false
+	for i := range clusters {
+		cluster := &clusters[i]
+		deleted, err := m.pruneObject(cluster, current[objectKey("Cluster", cluster)], contexts, now.Time)
+		if err != nil {
+			return pruned, errors.Wrap(err, "failed to prune cluster api cluster "+cluster.GetName())
+		}
+		if !deleted {
+			inUse[cluster.GetNamespace()] = true
+			remaining = true
+			continue
+		}
+
+		pruned = append(pruned, kccnv1alpha1.PrunedResource{
+			Kind:      cluster.GetKind(),
+			Name:      cluster.GetName(),
+			Namespace: cluster.GetNamespace(),
+			Context:   cluster.GetAnnotations()[AnnotationContext],
+			PrunedAt:  now,
+		})
+	}
+
+	status.ClusterAPIClusters = m.createsClusterAPIClusters() || remaining
+
+	namespaces := &corev1.NamespaceList{}
+	if err := m.client.List(m.context, namespaces, client.MatchingLabels(m.ownerLabels())); err != nil {
+		return pruned, errors.Wrap(err, "failed to list namespaces")
+	}
+
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if inUse[ns.Name] {
+			continue
+		}
+
+		deleted, err := m.pruneObject(ns, current[objectKey("Namespace", ns)], contexts, now.Time)
+		if err != nil {
+			return pruned, errors.Wrap(err, "failed to prune namespace "+ns.Name)
+		}
+		if deleted {
+			pruned = append(pruned, kccnv1alpha1.PrunedResource{
+				Kind:     "Namespace",
+				Name:     ns.Name,
+				Context:  ns.Annotations[AnnotationContext],
+				PrunedAt: now,
+			})
+		}
+	}
+
+	return pruned, nil
+}
+
+// currentObjects returns the keys of the objects the contexts are
+// currently exported to, as built by objectKey.
+func (m *Manager) currentObjects(contexts ContextList) map[string]bool {
+	current := map[string]bool{}
+	for _, ctx := range contexts {
+		t := m.targetFor(ctx)
+		current["Namespace//"+t.namespace] = true
+		current["Secret/"+t.namespace+"/"+t.secretName] = true
+
+		if m.cluster.Spec.ArgoCD != nil {
+			key := m.argoCDSecretKey(t)
+			current["Secret/"+key.Namespace+"/"+key.Name] = true
+		}
+
+		if t.format.ClusterAPI != nil && t.format.ClusterAPI.CreateCluster {
+			current["Cluster/"+t.namespace+"/"+clusterAPIName(t)] = true
+		}
+	}
+
+	return current
+}
+
+func objectKey(kind string, obj client.Object) string {
+	return kind + "/" + obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// pruneObject deletes obj if its context is missing from contexts and the
+// grace period has expired. If the context has reappeared, any pending
+// missing-since marker is removed. Objects whose context is present but
+// which are no longer current, as their context is now exported elsewhere,
+// are deleted straight away.
+func (m *Manager) pruneObject(obj client.Object, current bool, contexts ContextList, now time.Time) (bool, error) {
+	annotations := obj.GetAnnotations()
+	name, ok := annotations[AnnotationContext]
+	if !ok || obj.GetDeletionTimestamp() != nil {
+		return false, nil
+	}
+
+	_, found := contexts.Find(name)
+	if found && !current {
+		m.log.Info("pruning object no longer used by its context", "context", name,
+			"namespace", obj.GetNamespace(), "name", obj.GetName())
+		if err := m.client.Delete(m.context, obj); err != nil && !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if found {
+		if _, ok := annotations[AnnotationMissingSince]; ok {
+			delete(annotations, AnnotationMissingSince)
+			obj.SetAnnotations(annotations)
+			return false, m.client.Update(m.context, obj)
+		}
+		return false, nil
+	}
+
+	if grace := m.cluster.Spec.PruneGracePeriod.Duration; grace > 0 {
+		since, err := time.Parse(time.RFC3339, annotations[AnnotationMissingSince])
+		if err != nil {
+			m.log.Info("context missing from kubeconfig, waiting for grace period",
+				"context", name, "object", obj.GetName(), "grace", grace)
+			annotations[AnnotationMissingSince] = now.Format(time.RFC3339)
+			obj.SetAnnotations(annotations)
+			return false, m.client.Update(m.context, obj)
+		}
+
+		if now.Sub(since) < grace {
+			return false, nil
+		}
+	}
+
+	m.log.Info("pruning object for missing context", "context", name,
+		"namespace", obj.GetNamespace(), "name", obj.GetName())
+	if err := m.client.Delete(m.context, obj); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// prunedHistory appends pruned to the history already recorded in the
+// Cluster status, keeping only the most recent entries.
+func (m *Manager) prunedHistory(pruned []kccnv1alpha1.PrunedResource) []kccnv1alpha1.PrunedResource {
+	history := append([]kccnv1alpha1.PrunedResource{}, m.cluster.Status.Pruned...)
+	history = append(history, pruned...)
+	if len(history) > maxPrunedHistory {
+		history = history[len(history)-maxPrunedHistory:]
+	}
+
+	return history
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("Pruning", func() {
+	var m *Manager
+
+	owned := func(obj client.Object, context string) client.Object {
+		obj.SetLabels(map[string]string{
+			LabelManagedBy:        LabelManagedByValue,
+			LabelClusterName:      "sample",
+			LabelClusterNamespace: "default",
+		})
+		obj.SetAnnotations(map[string]string{AnnotationContext: context})
+		return obj
+	}
+
+	exists := func(obj client.Object) bool {
+		err := m.client.Get(m.context, client.ObjectKeyFromObject(obj), obj)
+		return err == nil
+	}
+
+	ginkgo.It("should prune objects left behind when an override moves a context", func() {
+		oldNamespace := owned(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-kind-tenant1"}},
+			"kind-tenant1")
+		oldSecret := owned(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "kind-tenant1-kubeconfig", Namespace: "cluster-kind-tenant1",
+		}}, "kind-tenant1")
+		newNamespace := owned(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant1"}}, "kind-tenant1")
+		newSecret := owned(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "tenant1", Namespace: "tenant1",
+		}}, "kind-tenant1")
+		m = newTestManager(oldNamespace, oldSecret, newNamespace, newSecret)
+		m.cluster.Spec.Overrides = []kccnv1alpha1.ContextOverride{
+			{Context: "kind-tenant1", Namespace: "tenant1", SecretName: "tenant1"},
+		}
+
+		pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(2))
+		Expect(exists(oldSecret)).To(BeFalse())
+		Expect(exists(oldNamespace)).To(BeFalse())
+		Expect(exists(newSecret)).To(BeTrue())
+		Expect(exists(newNamespace)).To(BeTrue())
+	})
+
+	ginkgo.It("should keep objects that are still current", func() {
+		namespace := owned(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "cluster-kind-tenant1"}},
+			"kind-tenant1")
+		secret := owned(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "kind-tenant1-kubeconfig", Namespace: "cluster-kind-tenant1",
+		}}, "kind-tenant1")
+		m = newTestManager(namespace, secret)
+
+		pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(BeEmpty())
+		Expect(exists(secret)).To(BeTrue())
+		Expect(exists(namespace)).To(BeTrue())
+	})
+
+	ginkgo.It("should prune objects for removed contexts", func() {
+		secret := owned(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name: "kind-tenant2-kubeconfig", Namespace: "cluster-kind-tenant2",
+		}}, "kind-tenant2")
+		m = newTestManager(secret)
+
+		pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pruned).To(HaveLen(1))
+		Expect(exists(secret)).To(BeFalse())
+	})
+
+	ginkgo.Context("with a grace period", func() {
+		var secret client.Object
+
+		ginkgo.BeforeEach(func() {
+			secret = owned(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Name: "kind-tenant2-kubeconfig", Namespace: "cluster-kind-tenant2",
+			}}, "kind-tenant2")
+		})
+
+		newManager := func() {
+			m = newTestManager(secret)
+			m.cluster.Spec.PruneGracePeriod = metav1.Duration{Duration: time.Hour}
+		}
+
+		missingSince := func(since time.Time) {
+			annotations := secret.GetAnnotations()
+			annotations[AnnotationMissingSince] = since.UTC().Format(time.RFC3339)
+			secret.SetAnnotations(annotations)
+		}
+
+		ginkgo.It("should mark objects the first time their context is missing", func() {
+			newManager()
+
+			pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(BeEmpty())
+			Expect(exists(secret)).To(BeTrue())
+
+			since, err := time.Parse(time.RFC3339, secret.GetAnnotations()[AnnotationMissingSince])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(since).To(BeTemporally("~", time.Now(), 5*time.Second))
+		})
+
+		ginkgo.It("should keep objects within the grace period", func() {
+			missingSince(time.Now().Add(-30 * time.Minute))
+			newManager()
+
+			pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(BeEmpty())
+			Expect(exists(secret)).To(BeTrue())
+		})
+
+		ginkgo.It("should prune objects once the grace period has expired", func() {
+			missingSince(time.Now().Add(-2 * time.Hour))
+			newManager()
+
+			pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(HaveLen(1))
+			Expect(exists(secret)).To(BeFalse())
+		})
+
+		ginkgo.It("should clear the mark when the context returns", func() {
+			missingSince(time.Now().Add(-30 * time.Minute))
+			newManager()
+
+			pruned, err := m.prune(&Status{}, ContextList{{name: "kind-tenant2"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pruned).To(BeEmpty())
+			Expect(exists(secret)).To(BeTrue())
+			Expect(secret.GetAnnotations()).NotTo(HaveKey(AnnotationMissingSince))
+		})
+	})
+})
@@ -17,46 +17,58 @@ package kubeconfig
 
 import (
 	"context"
+	"fmt"
 	"net"
-	"regexp"
-	"strings"
+	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/go-logr/logr"
 	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
-	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/aws"
-	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/clientcert"
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
 	"github.com/pkg/errors"
 )
 
 type Manager struct {
-	client  client.Client
-	context context.Context
-	cluster *kccnv1alpha1.Cluster
-	log     logr.Logger
+	client   client.Client
+	context  context.Context
+	cluster  *kccnv1alpha1.Cluster
+	log      logr.Logger
+	recorder record.EventRecorder
 }
 
 type Status struct {
 	ClusterStatus kccnv1alpha1.ClusterStatusEntries
 	FirewallRules []string
 	DeletionRules []string
+	Pruned        []kccnv1alpha1.PrunedResource
+	Conflicts     []kccnv1alpha1.KubeConfigConflict
+
+	// ClusterAPIClusters is true while placeholder Cluster API Clusters
+	// may exist for the Cluster.
+	ClusterAPIClusters bool
+
+	// NextRefresh is the earliest time at which a secret containing
+	// short lived credentials needs to be rewritten. It is zero when no
+	// secret holds expiring credentials.
+	NextRefresh time.Time
 }
 
 func NewManager(
-	ctx context.Context, r client.Client, cluster *kccnv1alpha1.Cluster,
+	ctx context.Context, r client.Client, recorder record.EventRecorder, cluster *kccnv1alpha1.Cluster,
 ) *Manager {
 	return &Manager{
-		client:  r,
-		context: ctx,
-		cluster: cluster,
-		log:     log.FromContext(ctx),
+		client:   r,
+		context:  ctx,
+		cluster:  cluster,
+		log:      log.FromContext(ctx),
+		recorder: recorder,
 	}
 }
 
@@ -67,12 +79,25 @@ func (m *Manager) ReconcileKubeconfig() (*Status, error) {
 		DeletionRules: []string{},
 	}
 
+	options, err := m.getOptions()
+	if err != nil {
+		m.recordLoadFailure()
+		return status, errors.Wrap(err, "failed to load kubeconfig")
+	}
+	status.Conflicts = options.conflicts
+	for _, conflict := range options.conflicts {
+		m.log.Info("conflicting kubeconfig entry", "kind", conflict.Kind, "name", conflict.Name,
+			"file", conflict.File, "ignored", conflict.Ignored)
+	}
+
 	// Get all contexts
 	allowedDomains := NewAllowedDomains(m.cluster.Spec.AdditionalDomains)
-	contexts, err := m.listContexts(allowedDomains)
+	contexts, err := m.listContexts(options, allowedDomains)
 	if err != nil {
+		m.recordLoadFailure()
 		return status, errors.Wrap(err, "failed to list contexts")
 	}
+	m.resetMetrics(contexts)
 
 	// Get the list of namespaces and check if the namespace exists
 	// If the namespace does not exist, create it
@@ -82,73 +107,134 @@ func (m *Manager) ReconcileKubeconfig() (*Status, error) {
 		return nil, errors.Wrap(err, "failed to list namespaces")
 	}
 
-	namespaceCleanup := make(map[string]bool)
 	// Create a namespace for each context
 	for _, ctx := range contexts {
+		if _, ok := m.cluster.Status.Clusters[ctx.name]; !ok {
+			m.event(EventReasonContextDiscovered, "discovered context %s", ctx.name)
+		}
 
-		config, err := ClientConfig(ctx.name, m.cluster.Spec.RemapToIp, m.getOptions())
+		config, err := ClientConfig(ctx.name, m.cluster.Spec.RemapToIp, options)
 		if err != nil {
 			m.log.Error(err, "failed to get client config", "context", ctx.name)
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonInvalidContext, err)
 			continue
 		}
 
-		var details *api.Config
-		{
-			switch config.provider {
-			case ProviderKindAWS:
-				details, err = aws.KubeConfig(ctx.cluster, config.Config)
-			case ProviderKindClientCert:
-				details, err = clientcert.KubeConfig(ctx.name, config.Config)
-			default:
-				m.log.Info("provider not supported", "provider", config.provider)
-				continue
-			}
+		p, err := m.providerFor(ctx, config)
+		if err != nil {
+			m.log.Info("provider not supported", "context", ctx.name, "reason", err.Error())
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonUnsupportedProvider, err)
+			continue
+		}
 
-			if err != nil {
-				m.log.Error(err, "failed to get kubeconfig", "context", ctx.name)
-				continue
-			}
+		details, expiry, err := p.KubeConfig(m.context, provider.Request{
+			Context:  ctx.name,
+			Cluster:  ctx.cluster,
+			User:     ctx.user,
+			AuthInfo: config.authInfo,
+			Config:   config.Config,
+			ResolvePath: func(path string) (string, error) {
+				return options.allowedPath(path, config.authInfo.LocationOfOrigin)
+			},
+			InCluster: options.inCluster,
+		})
+		if err != nil {
+			m.log.Error(err, "failed to get kubeconfig", "context", ctx.name, "provider", p.Name())
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonCredentialsFailed, err)
+			continue
 		}
 
-		re := regexp.MustCompile("[^a-zA-Z0-9]+")
-		var name = re.ReplaceAllString(ctx.name, "-")
+		t := m.targetFor(ctx)
+		if err = t.validate(); err != nil {
+			m.log.Info("invalid target", "context", ctx.name, "reason", err.Error())
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonInvalidTarget, err)
+			continue
+		}
+		details = renameContext(details, t.name)
+		m.recordExpiry(ctx.name, details, expiry)
 
-		var namespaceName string = name
-		namespacePrefix := m.cluster.Spec.NamespacePrefix
-		if namespacePrefix != "" {
-			namespacePrefix = strings.TrimSuffix(namespacePrefix, "-")
-			namespaceName = namespacePrefix + "-" + name
+		err = m.createNamespaceForCluster(t, *namespaces)
+		if err != nil {
+			m.log.Error(err, "failed to create namespace", "namespace", t.namespace)
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonNamespaceFailed, err)
+			continue
 		}
 
-		// TODO: Future improvement: Check if the namespace needs deleting
-		for _, ns := range namespaces.Items {
-			if ns.Name == namespaceName {
-				namespaceCleanup[ns.Name] = false
+		secretExpiry, err := m.createSecretForCluster(t, details, expiry)
+		if err != nil {
+			m.log.Error(err, "failed to create secret", "namespace", t.namespace, "secret", t.secretName)
+			// Writing to a secret that belongs to someone else will never
+			// succeed, so it is the target that is at fault.
+			reason := kccnv1alpha1.ReasonSecretFailed
+			var unmanaged *unmanagedSecretError
+			if errors.As(err, &unmanaged) {
+				reason = kccnv1alpha1.ReasonInvalidTarget
 			}
+			m.contextFailed(status, ctx.name, reason, err)
+			continue
 		}
 
-		err = m.createNamespaceForCluster(namespaceName, *namespaces)
-		if err != nil {
-			m.log.Error(err, "failed to create namespace", "namespace", ctx.name)
+		if err = m.createClusterAPIClusterForCluster(t, details); err != nil {
+			m.log.Error(err, "failed to create cluster api cluster", "namespace", t.namespace)
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonClusterAPIFailed, err)
 			continue
 		}
 
-		err = m.createSecretForCluster(namespaceName, name, details)
+		argoExpiry, err := m.createArgoCDSecretForCluster(t, details, expiry)
 		if err != nil {
-			m.log.Error(err, "failed to create secret", "namespace", namespaceName, "secret", ctx.name)
+			m.log.Error(err, "failed to create argo cd cluster secret", "context", ctx.name)
+			m.contextFailed(status, ctx.name, kccnv1alpha1.ReasonSecretFailed,
+				errors.Wrap(err, "argo cd cluster secret"))
 			continue
 		}
 
-		status.ClusterStatus[ctx.name] = kccnv1alpha1.ClusterStatusEntry{
-			Ready:          m.clusterAvailable(namespaceName, ctx.name+"-kubeconfig"),
-			Endpoint:       details.Clusters[ctx.name].Server,
-			KubeConfig:     ctx.name + "-kubeconfig",
+		expiry = earliest(secretExpiry, argoExpiry)
+
+		entry := kccnv1alpha1.ClusterStatusEntry{
+			Ready:          true,
+			Reason:         kccnv1alpha1.ReasonAvailable,
+			Message:        "cluster is reachable",
+			Endpoint:       details.Clusters[t.name].Server,
+			KubeConfig:     t.secretName,
+			Provider:       p.Name(),
 			LastUpdateTime: metav1.Now(),
 		}
 
+		probe := m.probeCluster(t.namespace, t.secretName, t.format.Keys[0])
+		entry.Probe = probe.status()
+		entry.Facts = probe.facts
+		m.recordProbe(ctx.name, probe.latency)
+		plugin := execPlugin(details)
+		switch {
+		case plugin != "" && (probe.err == nil ||
+			probe.category == kccnv1alpha1.ProbeCategoryUnauthorized ||
+			probe.category == kccnv1alpha1.ProbeCategoryForbidden):
+			// The probe runs without the plugin, so the cluster turning
+			// away an anonymous request still means it is reachable.
+			entry.Reason = kccnv1alpha1.ReasonPassthroughExec
+			entry.Message = fmt.Sprintf(
+				"cluster is reachable, credentials are provided by exec plugin %s which must be available to consumers of the secret",
+				plugin)
+		case probe.err != nil:
+			entry.Ready = false
+			entry.Reason, entry.Message = probe.reason()
+		}
+
+		if !expiry.IsZero() {
+			entry.ExpirationTime = &metav1.Time{Time: expiry}
+			refresh := expiry.Add(-CredentialRefreshWindow)
+			if status.NextRefresh.IsZero() || refresh.Before(status.NextRefresh) {
+				status.NextRefresh = refresh
+			}
+		}
+		m.setContextStatus(status, ctx.name, entry)
+
 		// Only add rules if the original IP is different from the remapped IP
 		if addr := net.ParseIP(config.originalIp); addr != nil && config.originalIp != config.remappedIp {
-			if !status.ClusterStatus[ctx.name].Ready {
+			// A firewall rule only helps when the server could not be
+			// reached, not when e.g. the certificate or credentials were
+			// rejected.
+			if isNetworkFailure(entry.Probe.Category) {
 				rule := m.makeFirewallRule(config.originalIp, config.remappedIp, config.port)
 				status.FirewallRules = append(status.FirewallRules, rule)
 			}
@@ -157,90 +243,175 @@ func (m *Manager) ReconcileKubeconfig() (*Status, error) {
 			status.DeletionRules = append(status.DeletionRules, rule)
 		}
 	}
+
+	// Remove namespaces and secrets for contexts that have gone away. A
+	// kubeconfig without any contexts is far more likely to be a missing
+	// mount or an empty source than every cluster having been deleted, so
+	// nothing is pruned.
+	var pruned []kccnv1alpha1.PrunedResource
+	status.ClusterAPIClusters = m.createsClusterAPIClusters() || m.cluster.Status.ClusterAPIClusters
+	if len(options.config.Contexts) == 0 {
+		m.log.Info("kubeconfig has no contexts, skipping prune")
+	} else if pruned, err = m.prune(status, contexts); err != nil {
+		m.log.Error(err, "failed to prune namespaces and secrets")
+	}
+	status.Pruned = m.prunedHistory(pruned)
+	for _, p := range pruned {
+		m.event(EventReasonPruned, "pruned %s %s for removed context %s", p.Kind, p.Name, p.Context)
+	}
+
+	if len(status.FirewallRules) > 0 && !slices.Equal(status.FirewallRules, m.cluster.Status.FirewallRules) {
+		m.warning(EventReasonFirewallRulesRequired,
+			"%d firewall rules are required to reach unreachable clusters, see status.firewallRules",
+			len(status.FirewallRules))
+	}
+	m.recordStatus(status, len(contexts))
+
 	return status, nil
 }
 
-func (m *Manager) createNamespaceForCluster(clusterName string, namespaces corev1.NamespaceList) error {
-	var exists bool
-	for _, ns := range namespaces.Items {
-		if ns.Name == clusterName {
-			exists = true
-			break
+// earliest returns the earlier of two expiry times, where the zero time
+// means the credentials do not expire.
+func earliest(a, b time.Time) time.Time {
+	if !b.IsZero() && (a.IsZero() || b.Before(a)) {
+		return b
+	}
+
+	return a
+}
+
+// contextFailed records a context that could not be exported.
+func (m *Manager) contextFailed(status *Status, name, reason string, err error) {
+	m.setContextStatus(status, name, kccnv1alpha1.ClusterStatusEntry{
+		Ready:          false,
+		Reason:         reason,
+		Message:        err.Error(),
+		LastUpdateTime: metav1.Now(),
+	})
+}
+
+// setContextStatus records the status entry for a context, carrying over
+// the last transition time if neither Ready nor Reason have changed.
+func (m *Manager) setContextStatus(status *Status, name string, entry kccnv1alpha1.ClusterStatusEntry) {
+	entry.LastTransitionTime = entry.LastUpdateTime
+	previous, ok := m.cluster.Status.Clusters[name]
+	if ok && previous.Ready == entry.Ready && previous.Reason == entry.Reason {
+		if !previous.LastTransitionTime.IsZero() {
+			entry.LastTransitionTime = previous.LastTransitionTime
+		}
+	} else {
+		m.contextEvent(name, entry.Ready, entry.Reason, entry.Message)
+		if !entry.Ready {
+			m.recordFailure(name, entry.Reason)
 		}
 	}
 
-	if exists {
+	status.ClusterStatus[name] = entry
+}
+
+func (m *Manager) createNamespaceForCluster(t target, namespaces corev1.NamespaceList) error {
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if ns.Name != t.namespace {
+			continue
+		}
+
+		// Only namespaces created for this cluster are kept in sync
+		if ns.Labels[LabelClusterName] != m.cluster.Name ||
+			ns.Labels[LabelClusterNamespace] != m.cluster.Namespace {
+			return nil
+		}
+
+		if !syncMetadata(ns, t.labels, t.annotations) {
+			return nil
+		}
+
+		if err := m.client.Update(m.context, ns); err != nil {
+			return errors.Wrap(err, "failed to update namespace")
+		}
 		return nil
 	}
 
 	ns := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: clusterName,
+			Name: t.namespace,
 		},
 	}
+	syncMetadata(ns, t.labels, t.annotations)
 
 	err := m.client.Create(m.context, ns)
 	if err != nil {
 		return errors.Wrap(err, "failed to create namespace")
 	}
+	m.event(EventReasonNamespaceCreated, "created namespace %s for context %s", t.namespace, t.context)
 
 	return nil
 }
 
-func (m *Manager) createSecretForCluster(namespace, clusterName string, details *api.Config) error {
-	// Get the list of secrets and check if the secret exists
-	// If the secret does not exist, create it
-	secrets := &corev1.SecretList{}
-	err := m.client.List(m.context, secrets)
+// createSecretForCluster creates or updates the kubeconfig secret for a
+// context.
+//
+// When expiry is set, the credentials in details are short lived and
+// regenerated on every reconciliation. To avoid rewriting the secret each
+// time, the credentials are excluded from the content hash and the secret
+// is only refreshed once the stored credentials are within
+// CredentialRefreshWindow of expiring. The expiry of the credentials held in
+// the secret is returned.
+func (m *Manager) createSecretForCluster(t target, details *api.Config, expiry time.Time) (time.Time, error) {
+	_ = api.MinifyConfig(details)
+	data, err := secretData(details, t.format)
 	if err != nil {
-		return errors.Wrap(err, "failed to list secrets")
+		return expiry, err
 	}
 
-	secretName := clusterName + "-kubeconfig"
-
-	var exists bool
-	for _, secret := range secrets.Items {
-		if secret.Name == secretName {
-			exists = true
-			break
+	hash := contentHash(data)
+	if !expiry.IsZero() {
+		// Hash the content without the short lived token so that
+		// regenerating the token does not change the hash.
+		stable, err := secretData(withoutTokens(details), t.format)
+		if err != nil {
+			return expiry, err
 		}
+		hash = contentHash(stable)
 	}
 
-	if !exists {
-		_ = api.MinifyConfig(details)
-		content, err := clientcmd.Write(*details)
-		if err != nil {
-			return errors.Wrap(err, "failed to write kubeconfig")
-		}
-		secret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      secretName,
-				Namespace: namespace,
-			},
-			Data: map[string][]byte{
-				"value": content,
-			},
+	d := desiredSecret{
+		namespace:   t.namespace,
+		name:        t.secretName,
+		labels:      t.labels,
+		annotations: t.annotations,
+		data:        data,
+		hash:        hash,
+		expiry:      expiry,
+		adopt:       t.adopt,
+	}
+
+	if t.format.ClusterAPI != nil {
+		d.secretType = ClusterAPISecretType
+		d.labels = map[string]string{
+			LabelClusterAPIClusterName: clusterAPIName(t),
 		}
-		err = m.client.Create(m.context, secret)
-		if err != nil {
-			return errors.Wrap(err, "failed to create secret")
+		for k, v := range t.labels {
+			d.labels[k] = v
 		}
 	}
 
-	return nil
+	return m.applySecret(d)
 }
 
-func (m *Manager) listContexts(allowedDomains AllowedDomains) (contexts ContextList, err error) {
-	options := m.getOptions()
-	config, err := options.configAccess.GetStartingConfig()
+func (m *Manager) listContexts(
+	options GetContextsOptions, allowedDomains AllowedDomains,
+) (contexts ContextList, err error) {
+	selector, err := NewContextSelector(m.cluster.Spec.Contexts)
 	if err != nil {
-		err = errors.Wrap(err, "cannot get starting config")
+		err = errors.Wrap(err, "invalid context selector")
 		return
 	}
 
+	config := options.config
 	for name, ctxConfig := range config.Contexts {
-		cluster := config.Clusters[ctxConfig.Cluster]
-		if !allowedDomains.Has(cluster.Server) {
+		cluster, ok := config.Clusters[ctxConfig.Cluster]
+		if !ok || !allowedDomains.Has(cluster.Server) {
 			continue
 		}
 
@@ -249,53 +420,12 @@ func (m *Manager) listContexts(allowedDomains AllowedDomains) (contexts ContextL
 			user:    ctxConfig.AuthInfo,
 			cluster: ctxConfig.Cluster,
 		}
+
+		if !selector.Matches(ctx) {
+			continue
+		}
 		contexts = append(contexts, ctx)
 	}
 
 	return
 }
-
-func (m *Manager) getOptions() GetContextsOptions {
-	pathOptions := clientcmd.NewDefaultPathOptions()
-	pathOptions.GlobalFile = m.cluster.Spec.KubeConfigPath
-	pathOptions.EnvVar = ""
-
-	return GetContextsOptions{configAccess: pathOptions}
-}
-
-func (m *Manager) clusterAvailable(namespace, secretName string) bool {
-	var (
-		err    error
-		config *rest.Config
-	)
-
-	// Get secret
-	secret := &corev1.Secret{}
-	if err = m.client.Get(m.context, client.ObjectKey{Namespace: namespace, Name: secretName}, secret); err != nil {
-		m.log.Error(err, "failed to get secret")
-		return false
-	}
-
-	// Build config
-	kubeconfig := secret.Data["value"]
-	config, err = clientcmd.RESTConfigFromKubeConfig(kubeconfig)
-	if err != nil {
-		m.log.Error(err, "failed to build config from kubeconfig", "kubeconfig", string(kubeconfig))
-		return false
-	}
-
-	// Test connection
-	client, err := client.New(config, client.Options{})
-	if err != nil {
-		m.log.Error(err, "failed to create clientset")
-		return false
-	}
-
-	err = client.List(m.context, &corev1.NamespaceList{})
-	if err != nil {
-		m.log.Error(err, "failed to get server version")
-		return false
-	}
-
-	return true
-}
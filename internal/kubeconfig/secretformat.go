@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/yaml"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// DefaultSecretKey is the key the kubeconfig is written under when no
+// secret format is given.
+const DefaultSecretKey = "value"
+
+// Keys written when a secret format requests split credentials.
+const (
+	SecretKeyServer = "server"
+	SecretKeyCA     = "ca.crt"
+	SecretKeyCert   = "tls.crt"
+	SecretKeyKey    = "tls.key"
+	SecretKeyToken  = "token"
+)
+
+// secretFormatFor returns the secret format for a context, preferring the
+// per context override over the Cluster wide setting.
+func (m *Manager) secretFormatFor(override kccnv1alpha1.ContextOverride) kccnv1alpha1.SecretFormat {
+	format := kccnv1alpha1.SecretFormat{}
+	switch {
+	case override.SecretFormat != nil:
+		format = *override.SecretFormat
+	case m.cluster.Spec.SecretFormat != nil:
+		format = *m.cluster.Spec.SecretFormat
+	}
+
+	if len(format.Keys) == 0 {
+		format.Keys = []string{DefaultSecretKey}
+	}
+
+	// Cluster API always reads the kubeconfig from the value key
+	if format.ClusterAPI != nil && !slices.Contains(format.Keys, DefaultSecretKey) {
+		format.Keys = append(slices.Clone(format.Keys), DefaultSecretKey)
+	}
+
+	return format
+}
+
+// validateSecretFormat checks that every key in the format holds only one
+// kind of data. Otherwise one would silently overwrite the other, e.g. the
+// JSON kubeconfig replacing the YAML one.
+func validateSecretFormat(format kccnv1alpha1.SecretFormat) error {
+	used := map[string]string{}
+	use := func(key, what string) error {
+		if previous, ok := used[key]; ok {
+			return errors.Errorf("secret key %q is used for both %s and %s", key, previous, what)
+		}
+		used[key] = what
+		return nil
+	}
+
+	for _, key := range format.Keys {
+		if err := use(key, "keys"); err != nil {
+			return err
+		}
+	}
+
+	if format.JSONKey != "" {
+		if err := use(format.JSONKey, "jsonKey"); err != nil {
+			return err
+		}
+	}
+
+	if format.Split {
+		for _, key := range []string{SecretKeyServer, SecretKeyCA, SecretKeyCert, SecretKeyKey, SecretKeyToken} {
+			if err := use(key, "split"); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// secretData renders details into the keys requested by format.
+func secretData(details *api.Config, format kccnv1alpha1.SecretFormat) (map[string][]byte, error) {
+	content, err := clientcmd.Write(*details)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write kubeconfig")
+	}
+
+	data := make(map[string][]byte)
+	for _, key := range format.Keys {
+		data[key] = content
+	}
+
+	if format.JSONKey != "" {
+		if data[format.JSONKey], err = yaml.YAMLToJSON(content); err != nil {
+			return nil, errors.Wrap(err, "failed to convert kubeconfig to json")
+		}
+	}
+
+	if !format.Split {
+		return data, nil
+	}
+
+	ctx, ok := details.Contexts[details.CurrentContext]
+	if !ok {
+		return data, nil
+	}
+
+	if cluster, ok := details.Clusters[ctx.Cluster]; ok {
+		data[SecretKeyServer] = []byte(cluster.Server)
+		setIfPresent(data, SecretKeyCA, cluster.CertificateAuthorityData)
+	}
+
+	if authInfo, ok := details.AuthInfos[ctx.AuthInfo]; ok {
+		setIfPresent(data, SecretKeyCert, authInfo.ClientCertificateData)
+		setIfPresent(data, SecretKeyKey, authInfo.ClientKeyData)
+		setIfPresent(data, SecretKeyToken, []byte(authInfo.Token))
+	}
+
+	return data, nil
+}
+
+func setIfPresent(data map[string][]byte, key string, value []byte) {
+	if len(value) > 0 {
+		data[key] = value
+	}
+}
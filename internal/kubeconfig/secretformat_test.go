@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"encoding/json"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("secretData", func() {
+	var details *api.Config
+
+	ginkgo.BeforeEach(func() {
+		details = api.NewConfig()
+		details.CurrentContext = "kind-tenant1"
+		details.Contexts["kind-tenant1"] = &api.Context{Cluster: "kind-tenant1", AuthInfo: "kind-tenant1"}
+		details.Clusters["kind-tenant1"] = &api.Cluster{
+			Server:                   "https://192.168.1.2:6443",
+			CertificateAuthorityData: []byte("ca"),
+		}
+		details.AuthInfos["kind-tenant1"] = &api.AuthInfo{
+			ClientCertificateData: []byte("cert"),
+			ClientKeyData:         []byte("key"),
+		}
+	})
+
+	ginkgo.It("should write the kubeconfig under value by default", func() {
+		m := newTestManager()
+		data, err := secretData(details, m.secretFormatFor(kccnv1alpha1.ContextOverride{}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveLen(1))
+
+		cfg, err := clientcmd.Load(data[DefaultSecretKey])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.CurrentContext).To(Equal("kind-tenant1"))
+	})
+
+	ginkgo.It("should write every requested layout", func() {
+		data, err := secretData(details, kccnv1alpha1.SecretFormat{
+			Keys:    []string{"value", "kubeconfig"},
+			JSONKey: "config.json",
+			Split:   true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(HaveKey("value"))
+		Expect(data).To(HaveKey("kubeconfig"))
+		Expect(json.Valid(data["config.json"])).To(BeTrue())
+		Expect(data).To(HaveKeyWithValue(SecretKeyServer, []byte("https://192.168.1.2:6443")))
+		Expect(data).To(HaveKeyWithValue(SecretKeyCA, []byte("ca")))
+		Expect(data).To(HaveKeyWithValue(SecretKeyCert, []byte("cert")))
+		Expect(data).To(HaveKeyWithValue(SecretKeyKey, []byte("key")))
+		Expect(data).NotTo(HaveKey(SecretKeyToken))
+	})
+
+	ginkgo.It("should prefer the per context format", func() {
+		m := newTestManager()
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{Keys: []string{"config"}}
+		format := m.secretFormatFor(kccnv1alpha1.ContextOverride{
+			SecretFormat: &kccnv1alpha1.SecretFormat{Split: true},
+		})
+		Expect(format.Keys).To(Equal([]string{DefaultSecretKey}))
+		Expect(format.Split).To(BeTrue())
+	})
+
+	ginkgo.It("should reject keys used for more than one kind of data", func() {
+		Expect(validateSecretFormat(kccnv1alpha1.SecretFormat{
+			Keys: []string{"value", "config"}, JSONKey: "config.json", Split: true,
+		})).To(Succeed())
+
+		Expect(validateSecretFormat(kccnv1alpha1.SecretFormat{
+			Keys: []string{"value"}, JSONKey: "value",
+		})).To(MatchError(`secret key "value" is used for both keys and jsonKey`))
+
+		Expect(validateSecretFormat(kccnv1alpha1.SecretFormat{
+			Keys: []string{"value", "token"}, Split: true,
+		})).To(MatchError(ContainSubstring(`"token" is used for both keys and split`)))
+
+		Expect(validateSecretFormat(kccnv1alpha1.SecretFormat{
+			Keys: []string{"value"}, JSONKey: "server", Split: true,
+		})).To(MatchError(ContainSubstring(`"server" is used for both jsonKey and split`)))
+	})
+
+	ginkgo.It("should reject a format that overwrites the cluster api key", func() {
+		m := newTestManager()
+		m.cluster.Spec.SecretFormat = &kccnv1alpha1.SecretFormat{
+			Keys:       []string{"config"},
+			JSONKey:    DefaultSecretKey,
+			ClusterAPI: &kccnv1alpha1.ClusterAPIFormat{},
+		}
+		Expect(m.targetFor(Context{name: "kind-tenant1"}).validate()).
+			To(MatchError(ContainSubstring(`"value" is used for both keys and jsonKey`)))
+	})
+})
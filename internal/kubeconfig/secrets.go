@@ -0,0 +1,228 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// desiredSecret is a secret the operator keeps in sync.
+type desiredSecret struct {
+	namespace   string
+	name        string
+	labels      map[string]string
+	annotations map[string]string
+	data        map[string][]byte
+
+	// secretType is the type of the secret. Defaults to Opaque.
+	secretType corev1.SecretType
+
+	// hash identifies the content of data. For short lived credentials
+	// it must be computed without the credentials themselves.
+	hash string
+
+	// expiry is when the credentials in data expire, if they do.
+	expiry time.Time
+
+	// adopt allows an existing secret without any labels to be taken
+	// over. Secrets written by earlier versions of the operator carry no
+	// ownership labels and would otherwise never be updated again.
+	adopt bool
+}
+
+// unmanagedSecretError is returned when the secret to be written already
+// exists and was not created for this Cluster.
+type unmanagedSecretError struct {
+	namespace string
+	name      string
+}
+
+func (e *unmanagedSecretError) Error() string {
+	return "secret exists and is not managed by this Cluster"
+}
+
+// applySecret creates the secret if it does not exist, or updates it if the
+// content hash has changed or the stored credentials are within
+// CredentialRefreshWindow of expiring. Secrets that were not created for
+// this Cluster are never modified and an unmanagedSecretError is returned
+// for them. The expiry of the credentials held in
+// the secret is returned.
+func (m *Manager) applySecret(d desiredSecret) (time.Time, error) {
+	if d.secretType == "" {
+		d.secretType = corev1.SecretTypeOpaque
+	}
+
+	annotations := map[string]string{
+		AnnotationContentHash: d.hash,
+	}
+	for k, v := range d.annotations {
+		annotations[k] = v
+	}
+	if !d.expiry.IsZero() {
+		annotations[AnnotationCredentialExpiry] = d.expiry.UTC().Format(time.RFC3339)
+	}
+
+	// Get the secret and check if it exists
+	// If the secret does not exist, create it
+	secret := &corev1.Secret{}
+	err := m.client.Get(m.context, client.ObjectKey{Namespace: d.namespace, Name: d.name}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = d.newSecret(annotations)
+		if err = m.client.Create(m.context, secret); err != nil {
+			return d.expiry, errors.Wrap(err, "failed to create secret")
+		}
+		m.recordSecretWrite("create")
+		m.event(EventReasonSecretCreated, "created secret %s/%s", d.namespace, d.name)
+		return d.expiry, nil
+	}
+
+	if err != nil {
+		return d.expiry, errors.Wrap(err, "failed to get secret")
+	}
+
+	// Never overwrite a secret that the operator did not create. Secrets
+	// created before the ownership labels existed are adopted by adding
+	// the labels along with the rest of the update below.
+	if secret.Labels[LabelClusterName] != m.cluster.Name ||
+		secret.Labels[LabelClusterNamespace] != m.cluster.Namespace {
+		if !d.adopt || len(secret.Labels) > 0 {
+			return time.Time{}, &unmanagedSecretError{namespace: d.namespace, name: d.name}
+		}
+
+		m.log.Info("adopting secret created by an earlier version of the operator",
+			"namespace", d.namespace, "secret", d.name)
+		secret.Labels = map[string]string{}
+		for k, v := range m.ownerLabels() {
+			secret.Labels[k] = v
+		}
+	}
+
+	// The type of a secret is immutable so it must be recreated if the
+	// output format has changed
+	if secret.Type != d.secretType {
+		m.log.Info("secret type changed, recreating secret", "namespace", d.namespace,
+			"secret", d.name, "from", secret.Type, "to", d.secretType)
+		if err = m.client.Delete(m.context, secret); err != nil && !apierrors.IsNotFound(err) {
+			return d.expiry, errors.Wrap(err, "failed to delete secret")
+		}
+
+		secret = d.newSecret(annotations)
+		if err = m.client.Create(m.context, secret); err != nil {
+			return d.expiry, errors.Wrap(err, "failed to create secret")
+		}
+		m.recordSecretWrite("create")
+		m.event(EventReasonSecretCreated, "created secret %s/%s", d.namespace, d.name)
+		return d.expiry, nil
+	}
+
+	// Keep user supplied labels and annotations in sync without touching
+	// the data
+	stored, storedErr := time.Parse(time.RFC3339, secret.Annotations[AnnotationCredentialExpiry])
+	metadataOnly := secret.Annotations[AnnotationContentHash] == d.hash &&
+		(d.expiry.IsZero() || (storedErr == nil && time.Until(stored) > CredentialRefreshWindow))
+
+	if metadataOnly {
+		if syncMetadata(secret, d.labels, d.annotations) {
+			if err = m.client.Update(m.context, secret); err != nil {
+				return d.expiry, errors.Wrap(err, "failed to update secret")
+			}
+		}
+
+		if d.expiry.IsZero() {
+			return d.expiry, nil
+		}
+		return stored, nil
+	}
+
+	// Only update the data when the content has changed or the stored
+	// credentials are about to expire
+	if secret.Annotations[AnnotationContentHash] == d.hash {
+		m.log.Info("credentials due to expire, refreshing secret", "namespace", d.namespace, "secret", d.name)
+	} else {
+		m.log.Info("kubeconfig changed, updating secret", "namespace", d.namespace, "secret", d.name)
+	}
+
+	delete(secret.Annotations, AnnotationCredentialExpiry)
+	syncMetadata(secret, d.labels, d.annotations)
+	for k, v := range annotations {
+		secret.Annotations[k] = v
+	}
+	secret.Data = d.data
+
+	if err = m.client.Update(m.context, secret); err != nil {
+		return d.expiry, errors.Wrap(err, "failed to update secret")
+	}
+	m.recordSecretWrite("update")
+	m.event(EventReasonSecretUpdated, "updated secret %s/%s", d.namespace, d.name)
+
+	return d.expiry, nil
+}
+
+// newSecret builds the secret described by d. annotations holds the
+// content hash and expiry along with the annotations of d.
+func (d desiredSecret) newSecret(annotations map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        d.name,
+			Namespace:   d.namespace,
+			Annotations: annotations,
+		},
+		Type: d.secretType,
+		Data: d.data,
+	}
+	syncMetadata(secret, d.labels, d.annotations)
+
+	return secret
+}
+
+// withoutTokens returns a copy of details with any bearer tokens removed.
+func withoutTokens(details *api.Config) *api.Config {
+	stable := details.DeepCopy()
+	for _, authInfo := range stable.AuthInfos {
+		authInfo.Token = ""
+	}
+
+	return stable
+}
+
+// contentHash returns a stable hash of the secret data.
+func contentHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
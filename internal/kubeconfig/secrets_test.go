@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("Applying secrets", func() {
+	var m *Manager
+
+	key := client.ObjectKey{Namespace: "cluster-kind-tenant1", Name: "kind-tenant1-kubeconfig"}
+
+	desired := func(content string) desiredSecret {
+		data := map[string][]byte{DefaultSecretKey: []byte(content)}
+		return desiredSecret{
+			namespace:   key.Namespace,
+			name:        key.Name,
+			labels:      m.ownerLabels(),
+			annotations: map[string]string{AnnotationContext: "kind-tenant1"},
+			data:        data,
+			hash:        contentHash(data),
+			adopt:       true,
+		}
+	}
+
+	get := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(m.client.Get(m.context, key, secret)).To(Succeed())
+		return secret
+	}
+
+	ginkgo.It("should create a missing secret", func() {
+		m = newTestManager()
+		_, err := m.applySecret(desired("v1"))
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := get()
+		Expect(secret.Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("v1")))
+		Expect(secret.Labels).To(HaveKeyWithValue(LabelClusterName, "sample"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(AnnotationContentHash, desired("v1").hash))
+	})
+
+	ginkgo.It("should update an owned secret when the content changes", func() {
+		m = newTestManager()
+		_, err := m.applySecret(desired("v1"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = m.applySecret(desired("v2"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(get().Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("v2")))
+	})
+
+	ginkgo.It("should adopt an unlabelled secret created by an earlier version", func() {
+		m = newTestManager(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string][]byte{DefaultSecretKey: []byte("stale")},
+		})
+
+		_, err := m.applySecret(desired("v1"))
+		Expect(err).NotTo(HaveOccurred())
+
+		secret := get()
+		Expect(secret.Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("v1")))
+		Expect(secret.Labels).To(HaveKeyWithValue(LabelClusterName, "sample"))
+		Expect(secret.Labels).To(HaveKeyWithValue(LabelClusterNamespace, "default"))
+		Expect(secret.Annotations).To(HaveKeyWithValue(AnnotationContext, "kind-tenant1"))
+	})
+
+	ginkgo.It("should not adopt secrets under an overridden name", func() {
+		m = newTestManager(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Data:       map[string][]byte{DefaultSecretKey: []byte("user")},
+		})
+
+		d := desired("v1")
+		d.adopt = false
+		_, err := m.applySecret(d)
+		Expect(err).To(MatchError("secret exists and is not managed by this Cluster"))
+		Expect(get().Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("user")))
+	})
+
+	ginkgo.It("should refuse secrets owned by someone else", func() {
+		m = newTestManager(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"app": "other"},
+			},
+			Data: map[string][]byte{DefaultSecretKey: []byte("foreign")},
+		})
+
+		expiry, err := m.applySecret(desired("v1"))
+		var unmanaged *unmanagedSecretError
+		Expect(errors.As(err, &unmanaged)).To(BeTrue())
+		Expect(expiry.IsZero()).To(BeTrue())
+
+		secret := get()
+		Expect(secret.Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("foreign")))
+		Expect(secret.Labels).NotTo(HaveKey(LabelClusterName))
+	})
+
+	ginkgo.It("should refuse secrets owned by another cluster", func() {
+		m = newTestManager(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{LabelClusterName: "other", LabelClusterNamespace: "default"},
+			},
+			Data: map[string][]byte{DefaultSecretKey: []byte("foreign")},
+		})
+
+		_, err := m.applySecret(desired("v1"))
+		Expect(err).To(MatchError("secret exists and is not managed by this Cluster"))
+		Expect(get().Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("foreign")))
+	})
+
+	ginkgo.It("should report the context when the secret is not managed by the cluster", func() {
+		m = newTestManager(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{"app": "other"},
+			},
+			Data: map[string][]byte{DefaultSecretKey: []byte("foreign")},
+		})
+		ginkgo.DeferCleanup(m.deleteMetrics)
+
+		config := api.NewConfig()
+		config.Contexts["kind-tenant1"] = &api.Context{Cluster: "kind-tenant1", AuthInfo: "kind-tenant1"}
+		config.Clusters["kind-tenant1"] = &api.Cluster{Server: "https://127.0.0.1:6443"}
+		config.AuthInfos["kind-tenant1"] = &api.AuthInfo{Token: "token"}
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "config")
+		Expect(clientcmd.WriteToFile(*config, path)).To(Succeed())
+		m.cluster.Spec.KubeConfigPath = path
+
+		status, err := m.ReconcileKubeconfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.ClusterStatus).To(HaveKeyWithValue("kind-tenant1", And(
+			HaveField("Ready", BeFalse()),
+			HaveField("Reason", kccnv1alpha1.ReasonInvalidTarget),
+			HaveField("Message", "secret exists and is not managed by this Cluster"),
+		)))
+		Expect(get().Data).To(HaveKeyWithValue(DefaultSecretKey, []byte("foreign")))
+	})
+})
+
+var _ = ginkgo.Describe("Refreshing short lived tokens", func() {
+	var (
+		m *Manager
+		t target
+	)
+
+	key := client.ObjectKey{Namespace: "cluster-kind-tenant1", Name: "kind-tenant1-kubeconfig"}
+
+	eksConfig := func(token string) *api.Config {
+		details := api.NewConfig()
+		details.CurrentContext = "kind-tenant1"
+		details.Contexts["kind-tenant1"] = &api.Context{Cluster: "kind-tenant1", AuthInfo: "kind-tenant1"}
+		details.Clusters["kind-tenant1"] = &api.Cluster{Server: "https://192.168.1.2:6443"}
+		details.AuthInfos["kind-tenant1"] = &api.AuthInfo{Token: token}
+		return details
+	}
+
+	token := func() string {
+		secret := &corev1.Secret{}
+		Expect(m.client.Get(m.context, key, secret)).To(Succeed())
+		config, err := clientcmd.Load(secret.Data[DefaultSecretKey])
+		Expect(err).NotTo(HaveOccurred())
+		return config.AuthInfos["kind-tenant1"].Token
+	}
+
+	ginkgo.BeforeEach(func() {
+		m = newTestManager()
+		t = m.targetFor(Context{name: "kind-tenant1"})
+	})
+
+	ginkgo.It("should write the token as a bearer token rather than an ExecCredential", func() {
+		_, err := m.createSecretForCluster(t, eksConfig("k8s-aws-v1.first"), time.Now().Add(14*time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token()).To(Equal("k8s-aws-v1.first"))
+	})
+
+	ginkgo.It("should keep the stored token until it is due to expire", func() {
+		first := time.Now().Add(14 * time.Minute).Truncate(time.Second)
+		_, err := m.createSecretForCluster(t, eksConfig("k8s-aws-v1.first"), first)
+		Expect(err).NotTo(HaveOccurred())
+
+		expiry, err := m.createSecretForCluster(t, eksConfig("k8s-aws-v1.second"), first.Add(time.Minute))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token()).To(Equal("k8s-aws-v1.first"))
+		Expect(expiry).To(BeTemporally("==", first))
+	})
+
+	ginkgo.It("should rewrite the token once it is within the refresh window", func() {
+		_, err := m.createSecretForCluster(t, eksConfig("k8s-aws-v1.first"), time.Now().Add(CredentialRefreshWindow/2))
+		Expect(err).NotTo(HaveOccurred())
+
+		next := time.Now().Add(14 * time.Minute)
+		expiry, err := m.createSecretForCluster(t, eksConfig("k8s-aws-v1.second"), next)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token()).To(Equal("k8s-aws-v1.second"))
+		Expect(expiry).To(Equal(next))
+	})
+})
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// pattern reports whether a value matches a glob or regular expression.
+type pattern func(string) bool
+
+// matcher is a compiled ContextMatcher.
+type matcher struct {
+	context pattern
+	cluster pattern
+	user    pattern
+}
+
+// ContextSelector decides which contexts are exported.
+type ContextSelector struct {
+	include []matcher
+	exclude []matcher
+}
+
+// NewContextSelector compiles the include and exclude lists from the
+// Cluster spec. A nil spec selects every context.
+func NewContextSelector(spec *kccnv1alpha1.ContextSelector) (*ContextSelector, error) {
+	selector := &ContextSelector{}
+	if spec == nil {
+		return selector, nil
+	}
+
+	var err error
+	if selector.include, err = compileMatchers(spec.Include); err != nil {
+		return nil, errors.Wrap(err, "invalid include")
+	}
+
+	if selector.exclude, err = compileMatchers(spec.Exclude); err != nil {
+		return nil, errors.Wrap(err, "invalid exclude")
+	}
+
+	return selector, nil
+}
+
+// Matches returns true if the context should be exported.
+func (s *ContextSelector) Matches(ctx Context) bool {
+	for _, m := range s.exclude {
+		if m.matches(ctx) {
+			return false
+		}
+	}
+
+	if len(s.include) == 0 {
+		return true
+	}
+
+	for _, m := range s.include {
+		if m.matches(ctx) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m matcher) matches(ctx Context) bool {
+	if m.context != nil && !m.context(ctx.name) {
+		return false
+	}
+
+	if m.cluster != nil && !m.cluster(ctx.cluster) {
+		return false
+	}
+
+	if m.user != nil && !m.user(ctx.user) {
+		return false
+	}
+
+	return true
+}
+
+func compileMatchers(specs []kccnv1alpha1.ContextMatcher) ([]matcher, error) {
+	matchers := make([]matcher, 0, len(specs))
+	for _, spec := range specs {
+		var (
+			m   matcher
+			err error
+		)
+
+		// An empty matcher would match, and in an exclude drop, every
+		// context.
+		if spec == (kccnv1alpha1.ContextMatcher{}) {
+			return nil, errors.New("matcher must set at least one of context, cluster or user")
+		}
+
+		if m.context, err = compilePattern(spec.Context); err != nil {
+			return nil, errors.Wrap(err, "context")
+		}
+
+		if m.cluster, err = compilePattern(spec.Cluster); err != nil {
+			return nil, errors.Wrap(err, "cluster")
+		}
+
+		if m.user, err = compilePattern(spec.User); err != nil {
+			return nil, errors.Wrap(err, "user")
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
+
+// compilePattern compiles a glob, or a regular expression if the pattern
+// is wrapped in slashes. An empty pattern returns nil and matches anything.
+func compilePattern(p string) (pattern, error) {
+	if p == "" {
+		return nil, nil
+	}
+
+	var expr string
+	if len(p) > 1 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+		expr = p[1 : len(p)-1]
+	} else {
+		expr = globToRegexp(p)
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid pattern %q", p)
+	}
+
+	return re.MatchString, nil
+}
+
+// globToRegexp converts a glob containing `*` and `?` wildcards into an
+// anchored regular expression. Unlike path.Match, `*` also matches `/` so
+// that ARN style names can be matched.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+
+	return b.String()
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+var _ = ginkgo.Describe("ContextSelector", func() {
+	var (
+		kind = Context{name: "kind-tenant1", cluster: "kind-tenant1", user: "kind-tenant1"}
+		mgmt = Context{name: "kind-management-cluster", cluster: "kind-management-cluster", user: "kind-management-cluster"}
+		eks  = Context{
+			name:    "arn:aws:eks:us-east-1:000000000000:cluster/eks1",
+			cluster: "arn:aws:eks:us-east-1:000000000000:cluster/eks1",
+			user:    "arn:aws:eks:us-east-1:000000000000:cluster/eks1",
+		}
+	)
+
+	ginkgo.It("should select everything when unset", func() {
+		selector, err := NewContextSelector(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(kind)).To(BeTrue())
+		Expect(selector.Matches(eks)).To(BeTrue())
+	})
+
+	ginkgo.It("should match globs across slashes", func() {
+		selector, err := NewContextSelector(&kccnv1alpha1.ContextSelector{
+			Include: []kccnv1alpha1.ContextMatcher{{Cluster: "arn:aws:eks:*:cluster/*"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(eks)).To(BeTrue())
+		Expect(selector.Matches(kind)).To(BeFalse())
+	})
+
+	ginkgo.It("should give exclusions precedence over inclusions", func() {
+		selector, err := NewContextSelector(&kccnv1alpha1.ContextSelector{
+			Include: []kccnv1alpha1.ContextMatcher{{Context: "kind-*"}},
+			Exclude: []kccnv1alpha1.ContextMatcher{{Context: "/^kind-management/"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(kind)).To(BeTrue())
+		Expect(selector.Matches(mgmt)).To(BeFalse())
+		Expect(selector.Matches(eks)).To(BeFalse())
+	})
+
+	ginkgo.It("should require every field of a matcher to match", func() {
+		selector, err := NewContextSelector(&kccnv1alpha1.ContextSelector{
+			Include: []kccnv1alpha1.ContextMatcher{{Context: "kind-*", User: "nobody"}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(selector.Matches(kind)).To(BeFalse())
+	})
+
+	ginkgo.It("should reject invalid regular expressions", func() {
+		_, err := NewContextSelector(&kccnv1alpha1.ContextSelector{
+			Exclude: []kccnv1alpha1.ContextMatcher{{Context: "/kind-(/"}},
+		})
+		Expect(err).To(HaveOccurred())
+	})
+
+	ginkgo.It("should reject empty matchers", func() {
+		_, err := NewContextSelector(&kccnv1alpha1.ContextSelector{
+			Include: []kccnv1alpha1.ContextMatcher{{Context: "kind-*"}},
+			Exclude: []kccnv1alpha1.ContextMatcher{{}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("at least one of context, cluster or user")))
+	})
+})
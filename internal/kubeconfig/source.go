@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// getOptions loads the source kubeconfig for the Cluster, either from a
+// Secret or ConfigMap referenced by KubeConfigSource, or by merging the
+// files named by KubeConfigPath, KubeConfigPaths and KubeConfigDir.
+func (m *Manager) getOptions() (GetContextsOptions, error) {
+	if m.cluster.Spec.KubeConfigSource != nil {
+		config, err := m.loadFromSource(m.cluster.Spec.KubeConfigSource)
+		if err != nil {
+			return GetContextsOptions{}, err
+		}
+		return GetContextsOptions{
+			config:        config,
+			inCluster:     true,
+			tlsServerName: m.cluster.Spec.SetTLSServerName,
+		}, nil
+	}
+
+	files, err := m.files()
+	if err != nil {
+		return GetContextsOptions{}, err
+	}
+
+	config, conflicts, err := loadFiles(files)
+	if err != nil {
+		return GetContextsOptions{}, err
+	}
+
+	return GetContextsOptions{
+		config:        config,
+		conflicts:     conflicts,
+		pathMappings:  m.cluster.Spec.HostPathMappings,
+		tlsServerName: m.cluster.Spec.SetTLSServerName,
+	}, nil
+}
+
+// loadFromSource reads a kubeconfig from a Secret or ConfigMap.
+func (m *Manager) loadFromSource(source *kccnv1alpha1.KubeConfigSource) (*api.Config, error) {
+	// The source is only read from the namespace of the Cluster, so that
+	// creating a Cluster does not grant access to Secrets elsewhere.
+	key := client.ObjectKey{
+		Namespace: m.cluster.Namespace,
+		Name:      source.Name,
+	}
+
+	dataKey := source.Key
+	if dataKey == "" {
+		dataKey = DefaultSecretKey
+	}
+
+	var (
+		content []byte
+		ok      bool
+	)
+	switch source.Kind {
+	case kccnv1alpha1.KubeConfigSourceSecret:
+		secret := &corev1.Secret{}
+		if err := m.client.Get(m.context, key, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to get secret %s", key)
+		}
+		content, ok = secret.Data[dataKey]
+	case kccnv1alpha1.KubeConfigSourceConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := m.client.Get(m.context, key, configMap); err != nil {
+			return nil, errors.Wrapf(err, "failed to get configmap %s", key)
+		}
+		var value string
+		if value, ok = configMap.Data[dataKey]; ok {
+			content = []byte(value)
+		} else {
+			content, ok = configMap.BinaryData[dataKey]
+		}
+	default:
+		return nil, errors.Errorf("unsupported kubeconfig source kind %q", source.Kind)
+	}
+
+	if !ok {
+		return nil, errors.Errorf("key %q not found in %s %s", dataKey, source.Kind, key)
+	}
+
+	config, err := clientcmd.Load(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse kubeconfig from %s %s", source.Kind, key)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+const sourceKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: kind-tenant1
+  cluster:
+    server: https://192.168.1.2:6443
+contexts:
+- name: kind-tenant1
+  context:
+    cluster: kind-tenant1
+    user: kind-tenant1
+users:
+- name: kind-tenant1
+  user:
+    token: abc
+`
+
+var _ = ginkgo.Describe("Kubeconfig sources", func() {
+	newManager := func(source *kccnv1alpha1.KubeConfigSource, objects ...client.Object) *Manager {
+		m := newTestManager(objects...)
+		m.cluster.Spec.KubeConfigSource = source
+		return m
+	}
+
+	ginkgo.It("should load a kubeconfig from a secret", func() {
+		m := newManager(&kccnv1alpha1.KubeConfigSource{
+			Kind: kccnv1alpha1.KubeConfigSourceSecret,
+			Name: "kubeconfig",
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "default"},
+			Data:       map[string][]byte{DefaultSecretKey: []byte(sourceKubeconfig)},
+		})
+
+		options, err := m.getOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.config.Contexts).To(HaveKey("kind-tenant1"))
+	})
+
+	ginkgo.It("should load a kubeconfig from a configmap", func() {
+		m := newManager(&kccnv1alpha1.KubeConfigSource{
+			Kind: kccnv1alpha1.KubeConfigSourceConfigMap,
+			Name: "kubeconfig",
+			Key:  "config",
+		})
+		Expect(m.client.Create(m.context, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "default"},
+			Data:       map[string]string{"config": sourceKubeconfig},
+		})).To(Succeed())
+
+		options, err := m.getOptions()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(options.config.Clusters["kind-tenant1"].Server).To(Equal("https://192.168.1.2:6443"))
+	})
+
+	ginkgo.It("should fail when the key is missing", func() {
+		m := newManager(&kccnv1alpha1.KubeConfigSource{
+			Kind: kccnv1alpha1.KubeConfigSourceSecret,
+			Name: "kubeconfig",
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "default"},
+			Data:       map[string][]byte{"other": []byte(sourceKubeconfig)},
+		})
+
+		_, err := m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring(`key "value" not found`)))
+	})
+
+	ginkgo.It("should only read the source from the namespace of the cluster", func() {
+		m := newManager(&kccnv1alpha1.KubeConfigSource{
+			Kind: kccnv1alpha1.KubeConfigSourceSecret,
+			Name: "kubeconfig",
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "kube-system"},
+			Data:       map[string][]byte{DefaultSecretKey: []byte(sourceKubeconfig)},
+		})
+
+		_, err := m.getOptions()
+		Expect(err).To(MatchError(ContainSubstring("default/kubeconfig")))
+	})
+
+	ginkgo.It("should not prune when the source has no contexts", func() {
+		m := newManager(&kccnv1alpha1.KubeConfigSource{
+			Kind: kccnv1alpha1.KubeConfigSourceSecret,
+			Name: "kubeconfig",
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "default"},
+			Data:       map[string][]byte{DefaultSecretKey: []byte("apiVersion: v1\nkind: Config\n")},
+		}, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "kind-tenant1-kubeconfig",
+				Namespace:   "cluster-kind-tenant1",
+				Labels:      map[string]string{LabelClusterName: "sample", LabelClusterNamespace: "default"},
+				Annotations: map[string]string{AnnotationContext: "kind-tenant1"},
+			},
+		})
+		m.cluster.Spec.KubeConfigSource.Key = DefaultSecretKey
+		ginkgo.DeferCleanup(m.deleteMetrics)
+
+		status, err := m.ReconcileKubeconfig()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.Pruned).To(BeEmpty())
+		Expect(m.client.Get(m.context, client.ObjectKey{
+			Namespace: "cluster-kind-tenant1", Name: "kind-tenant1-kubeconfig",
+		}, &corev1.Secret{})).To(Succeed())
+	})
+
+	ginkgo.It("should fail when the kubeconfig file is missing", func() {
+		m := newManager(nil)
+		m.cluster.Spec.KubeConfigPath = "/does/not/exist"
+
+		_, err := m.getOptions()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package kubeconfig
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
+
+// ownerLabels are the labels used to track the namespaces and secrets
+// created on behalf of the Cluster.
+func (m *Manager) ownerLabels() map[string]string {
+	return map[string]string{
+		LabelManagedBy:        LabelManagedByValue,
+		LabelClusterName:      m.cluster.Name,
+		LabelClusterNamespace: m.cluster.Namespace,
+	}
+}
+
+// Teardown removes the namespaces, secrets and placeholder Cluster API
+// Clusters created for the Cluster.
+//
+// Only objects carrying the ownership labels of this Cluster are removed.
+// Namespaces which existed before the operator ran are never labelled and
+// are therefore left alone. When the deletion policy is Retain, nothing is
+// removed.
+func (m *Manager) Teardown() error {
+	m.deleteMetrics()
+
+	if m.cluster.Spec.DeletionPolicy == kccnv1alpha1.DeletionPolicyRetain {
+		m.log.Info("deletion policy is Retain, leaving namespaces and secrets in place")
+		return nil
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := m.client.List(m.context, secrets, client.MatchingLabels(m.ownerLabels())); err != nil {
+		return errors.Wrap(err, "failed to list secrets")
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		m.log.Info("deleting secret", "namespace", secret.Namespace, "secret", secret.Name)
+		if err := m.client.Delete(m.context, secret); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete secret "+secret.Name)
+		}
+	}
+
+	clusters, err := m.ownedClusterAPIClusters()
+	if err != nil {
+		return err
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		m.log.Info("deleting cluster api cluster", "namespace", cluster.GetNamespace(), "name", cluster.GetName())
+		if err := m.client.Delete(m.context, cluster); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete cluster api cluster "+cluster.GetName())
+		}
+	}
+
+	namespaces := &corev1.NamespaceList{}
+	if err := m.client.List(m.context, namespaces, client.MatchingLabels(m.ownerLabels())); err != nil {
+		return errors.Wrap(err, "failed to list namespaces")
+	}
+
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		m.log.Info("deleting namespace", "namespace", ns.Name)
+		if err := m.client.Delete(m.context, ns); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrap(err, "failed to delete namespace "+ns.Name)
+		}
+	}
+
+	return nil
+}
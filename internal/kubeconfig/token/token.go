@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+// Name is the name of the bearer token and basic auth provider.
+const Name = "token"
+
+// Provider exports contexts authenticated with a static bearer token,
+// a token file, or a username and password. Any client certificate
+// alongside the token is exported too.
+type Provider struct{}
+
+var _ provider.Provider = Provider{}
+
+func (Provider) Name() string {
+	return Name
+}
+
+// Detect returns true if the user has a token, a token file, or a
+// username and password.
+func (Provider) Detect(authInfo *api.AuthInfo) bool {
+	if authInfo.Exec != nil || authInfo.AuthProvider != nil {
+		return false
+	}
+
+	return authInfo.Token != "" || authInfo.TokenFile != "" ||
+		(authInfo.Username != "" && authInfo.Password != "")
+}
+
+// KubeConfig copies the credentials into the exported kubeconfig. The
+// token file is read when the client config is built, so the token itself
+// is exported rather than a path that does not exist for the consumer.
+// Token files are subject to the same restrictions as other file
+// references and are refused for kubeconfigs loaded from the cluster.
+func (Provider) KubeConfig(_ context.Context, req provider.Request) (*api.Config, time.Time, error) {
+	user := &api.AuthInfo{
+		Token:                 req.Config.BearerToken,
+		ClientCertificateData: req.Config.CertData,
+		ClientKeyData:         req.Config.KeyData,
+	}
+	if req.AuthInfo.Username != "" && req.AuthInfo.Password != "" {
+		user.Username = req.AuthInfo.Username
+		user.Password = req.AuthInfo.Password
+	}
+
+	cfg := &api.Config{
+		APIVersion: api.SchemeGroupVersion.Version,
+		Clusters: map[string]*api.Cluster{
+			req.Context: {
+				Server:                   req.Config.Host,
+				CertificateAuthorityData: req.Config.CAData,
+				TLSServerName:            req.Config.ServerName,
+			},
+		},
+		Contexts: map[string]*api.Context{
+			req.Context: {
+				Cluster:  req.Context,
+				AuthInfo: req.User,
+			},
+		},
+		CurrentContext: req.Context,
+		AuthInfos: map[string]*api.AuthInfo{
+			req.User: user,
+		},
+	}
+
+	return cfg, time.Time{}, nil
+}
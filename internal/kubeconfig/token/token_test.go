@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package token
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/mproffitt/kubeconfig-operator/internal/kubeconfig/provider"
+)
+
+var _ = ginkgo.Describe("Token", func() {
+	ginkgo.It("should detect tokens, token files and basic auth", func() {
+		Expect(Provider{}.Detect(&api.AuthInfo{Token: "abc"})).To(BeTrue())
+		Expect(Provider{}.Detect(&api.AuthInfo{TokenFile: "/tmp/token"})).To(BeTrue())
+		Expect(Provider{}.Detect(&api.AuthInfo{Username: "admin", Password: "secret"})).To(BeTrue())
+
+		Expect(Provider{}.Detect(&api.AuthInfo{Username: "admin"})).To(BeFalse())
+		Expect(Provider{}.Detect(&api.AuthInfo{
+			Token: "abc", Exec: &api.ExecConfig{Command: "aws"},
+		})).To(BeFalse())
+		Expect(Provider{}.Detect(&api.AuthInfo{
+			Token: "abc", AuthProvider: &api.AuthProviderConfig{Name: "oidc"},
+		})).To(BeFalse())
+	})
+
+	ginkgo.It("should export the token read by the client config", func() {
+		details, expiry, err := Provider{}.KubeConfig(context.Background(), provider.Request{
+			Context:  "k3s",
+			Cluster:  "k3s",
+			User:     "k3s-admin",
+			AuthInfo: &api.AuthInfo{TokenFile: "/var/lib/rancher/k3s/token"},
+			Config: &rest.Config{
+				Host:            "https://192.168.1.2:6443",
+				BearerToken:     "abc",
+				TLSClientConfig: rest.TLSClientConfig{CAData: []byte("ca")},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expiry.IsZero()).To(BeTrue())
+
+		user := details.AuthInfos["k3s-admin"]
+		Expect(user.Token).To(Equal("abc"))
+		Expect(user.TokenFile).To(BeEmpty())
+		Expect(details.Clusters["k3s"].CertificateAuthorityData).To(Equal([]byte("ca")))
+		Expect(details.CurrentContext).To(Equal("k3s"))
+	})
+
+	ginkgo.It("should export basic auth credentials", func() {
+		details, _, err := Provider{}.KubeConfig(context.Background(), provider.Request{
+			Context:  "dev",
+			Cluster:  "dev",
+			User:     "admin",
+			AuthInfo: &api.AuthInfo{Username: "admin", Password: "secret"},
+			Config:   &rest.Config{Host: "https://192.168.1.2:6443"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(details.AuthInfos["admin"].Username).To(Equal("admin"))
+		Expect(details.AuthInfos["admin"].Password).To(Equal("secret"))
+		Expect(details.AuthInfos["admin"].Token).To(BeEmpty())
+	})
+})
@@ -1,22 +1,63 @@
 package kubeconfig
 
 import (
+	"time"
+
 	"github.com/mproffitt/kubeconfig-operator/internal/helpers"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-)
+	"k8s.io/client-go/tools/clientcmd/api"
 
-type ProviderKind string
+	kccnv1alpha1 "github.com/mproffitt/kubeconfig-operator/api/v1alpha1"
+)
 
 const (
-	ProviderKindUnknown    ProviderKind = "unknown"
-	ProviderKindAWS        ProviderKind = "aws"
-	ProviderKindAzure      ProviderKind = "azure"
-	ProviderKindGCP        ProviderKind = "gcp"
-	ProviderKindOIDC       ProviderKind = "oidc"
-	ProviderKindClientCert ProviderKind = "client-cert"
+	// LabelManagedBy is set on every namespace and secret created by the
+	// operator.
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	// LabelManagedByValue is the value of LabelManagedBy.
+	LabelManagedByValue = "kubeconfig-operator"
+
+	// LabelClusterName is the name of the Cluster that owns the object.
+	LabelClusterName = "kubeconfig.choclab.net/cluster-name"
+
+	// LabelClusterNamespace is the namespace of the Cluster that owns the
+	// object.
+	LabelClusterNamespace = "kubeconfig.choclab.net/cluster-namespace"
+
+	// LabelKubeConfigSource marks a Secret or ConfigMap used as a
+	// kubeConfigSource. Only objects with this label set to "true" are
+	// watched for changes.
+	LabelKubeConfigSource = "kubeconfig.choclab.net/kubeconfig-source"
+
+	// AnnotationContext is the kubeconfig context the object was created
+	// for. Context names are not valid label values so this is stored as
+	// an annotation.
+	AnnotationContext = "kubeconfig.choclab.net/context"
+
+	// AnnotationMissingSince records when the context of an object was
+	// first seen to be missing from the kubeconfig. It is used to honour
+	// the prune grace period.
+	AnnotationMissingSince = "kubeconfig.choclab.net/missing-since"
+
+	// AnnotationAppliedMetadata records the label and annotation keys the
+	// operator last applied to an object, so that keys removed from an
+	// override can be removed from the object.
+	AnnotationAppliedMetadata = "kubeconfig.choclab.net/applied-metadata"
+
+	// AnnotationContentHash is a hash of the data written to a secret,
+	// used to detect when the source context has changed.
+	AnnotationContentHash = "kubeconfig.choclab.net/content-hash"
+
+	// AnnotationCredentialExpiry records when the credentials held in a
+	// secret expire, for providers that issue short lived tokens.
+	AnnotationCredentialExpiry = "kubeconfig.choclab.net/credential-expiry"
 )
 
+// CredentialRefreshWindow is how long before expiry short lived
+// credentials are rewritten.
+const CredentialRefreshWindow = 5 * time.Minute
+
 var DefaultAllowedDomains = AllowedDomains{
 	"cluster.local",
 	"localhost",
@@ -64,7 +105,17 @@ func (a AllowedDomains) Has(domain string) bool {
 
 // GetContextsOptions is the options for getting contexts.
 type GetContextsOptions struct {
-	configAccess clientcmd.ConfigAccess
+	config       *api.Config
+	conflicts    []kccnv1alpha1.KubeConfigConflict
+	pathMappings []kccnv1alpha1.HostPathMapping
+
+	// inCluster is set when the kubeconfig was loaded from a Secret or
+	// ConfigMap rather than a file mounted from the host.
+	inCluster bool
+
+	// tlsServerName sets the TLS server name to the original host when
+	// the server address is remapped.
+	tlsServerName bool
 }
 
 // Context is a reference to a context in a kubeconfig.
@@ -74,9 +125,10 @@ type Context struct {
 	cluster string
 }
 
-// kconfig is a struct that holds the provider type and the rest config.
+// kconfig is a struct that holds the user and the rest config for a
+// context.
 type kconfig struct {
-	provider   ProviderKind
+	authInfo   *api.AuthInfo
 	originalIp string
 	remappedIp string
 	port       string